@@ -0,0 +1,252 @@
+// Command scaleadmin maintains the stored readings while the server is
+// stopped.
+//
+//	scaleadmin [-storage STORAGE] <command> [flags]
+//
+// The storage defaults to SCALE_STORAGE, as for the server.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/scale/src/scale/maintenance"
+	scalerepo "github.com/scale/src/scale/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, repo domain.ScaleRepository, args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = map[string]command{
+	"migrate":   {"bring readings stored by older versions up to date", migrate},
+	"validate":  {"report readings breaking max >= min or difference", validate},
+	"dedupe":    {"keep only the newest reading of every day", fix("dedupe", maintenance.Dedupe)},
+	"recompute": {"set the difference of every reading from its min and max", fix("recompute", maintenance.Recompute)},
+	"backup":    {"write every reading, deleted ones included, to a file", backup},
+	"restore":   {"replace every reading with the ones of a backup", restore},
+}
+
+var commandOrder = []string{"migrate", "validate", "dedupe", "recompute", "backup", "restore"}
+
+var errMemoryStorage = errors.New("the memory storage only lives in the server process, set -storage or SCALE_STORAGE to a persistent one such as file:PATH")
+
+// open is replaced by tests
+var open = openStorage
+
+// openStorage is scalerepo.Open refusing storages gone with the server.
+func openStorage(storage string) (domain.ScaleRepository, error) {
+	if storage == "" || storage == scalerepo.StorageMemory {
+		return nil, errMemoryStorage
+	}
+	return scalerepo.Open(storage, logrus.StandardLogger())
+}
+
+func init() {
+	helper.InitTime()
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "scaleadmin:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("scaleadmin", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	storage := fs.String("storage", os.Getenv("SCALE_STORAGE"), "storage of the readings")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: scaleadmin [flags] <command> [command flags]")
+		fmt.Fprintln(stderr, "\nStop the server first, it does not see changes made behind its back.")
+		fmt.Fprintln(stderr, "\ncommands:")
+		for _, name := range commandOrder {
+			fmt.Fprintf(stderr, "  %-9s %s\n", name, commands[name].usage)
+		}
+		fmt.Fprintln(stderr, "\nflags:")
+		fs.PrintDefaults()
+	}
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+	repo, err := open(*storage)
+	if err != nil {
+		return err
+	}
+	err = cmd.run(context.Background(), repo, fs.Args()[1:], stdin, stdout)
+	// releases the file and its lock for the server
+	if closer, ok := repo.(io.Closer); ok {
+		closeErr := closer.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func migrate(ctx context.Context, repo domain.ScaleRepository, args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "only report what would change")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	var changed map[string]int
+	_, err = maintenance.Apply(ctx, repo, *dryRun, func(scales []domain.Scale) ([]domain.Scale, int) {
+		scales, changed = maintenance.Migrate(scales)
+		total := 0
+		for _, count := range changed {
+			total += count
+		}
+		return scales, total
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range maintenance.Migrations {
+		fmt.Fprintf(stdout, "%s: %d readings\n", migration.Name, changed[migration.Name])
+	}
+	if *dryRun {
+		fmt.Fprintln(stdout, "dry run, nothing written")
+	}
+	return nil
+}
+
+func validate(ctx context.Context, repo domain.ScaleRepository, args []string, stdin io.Reader, stdout io.Writer) error {
+	err := flag.NewFlagSet("validate", flag.ContinueOnError).Parse(args)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := repo.Dump(ctx)
+	if err != nil {
+		return err
+	}
+	problems := maintenance.Validate(snapshot.Scales)
+	if len(problems) == 0 {
+		fmt.Fprintf(stdout, "%d readings are valid\n", len(snapshot.Scales))
+		return nil
+	}
+
+	printProblems(stdout, problems)
+	return fmt.Errorf("%d problems found", len(problems))
+}
+
+func printProblems(stdout io.Writer, problems []maintenance.Problem) {
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tVERSION\tPROBLEM")
+	for _, problem := range problems {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", problem.Date, problem.Version, problem.Reason)
+	}
+	w.Flush()
+}
+
+// fix runs the maintenance fix as the command name.
+func fix(name string, fix maintenance.Fix) func(ctx context.Context, repo domain.ScaleRepository, args []string, stdin io.Reader, stdout io.Writer) error {
+	return func(ctx context.Context, repo domain.ScaleRepository, args []string, stdin io.Reader, stdout io.Writer) error {
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		dryRun := fs.Bool("dry-run", false, "only report what would change")
+		err := fs.Parse(args)
+		if err != nil {
+			return err
+		}
+
+		changed, err := maintenance.Apply(ctx, repo, *dryRun, fix)
+		if err != nil {
+			return err
+		}
+		if *dryRun {
+			fmt.Fprintf(stdout, "%s would change %d readings\n", name, changed)
+			return nil
+		}
+		fmt.Fprintf(stdout, "%s changed %d readings\n", name, changed)
+		return nil
+	}
+}
+
+func backup(ctx context.Context, repo domain.ScaleRepository, args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	file := fs.String("file", "-", "file to write, - for stdout")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	backup, err := maintenance.Backup(ctx, repo)
+	if err != nil {
+		return err
+	}
+	if *file == "-" {
+		return maintenance.WriteBackup(stdout, backup)
+	}
+	f, err := os.Create(*file)
+	if err != nil {
+		return err
+	}
+	err = maintenance.WriteBackup(f, backup)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func restore(ctx context.Context, repo domain.ScaleRepository, args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	file := fs.String("file", "-", "file to read, - for stdin")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	r := stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	backup, err := maintenance.ReadBackup(r)
+	if err != nil {
+		return err
+	}
+	err = maintenance.Restore(ctx, repo, backup)
+	var invalid *maintenance.InvalidBackupError
+	if errors.As(err, &invalid) {
+		printProblems(stdout, invalid.Problems)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "restored %d readings\n", len(backup.Snapshot.Scales))
+	return nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	scalerepo "github.com/scale/src/scale/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	scales := []domain.Scale{
+		{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1},
+		{Date: date, Min: 46, Max: 50, Difference: 0, Version: 2},
+		{Date: date.AddDate(0, 0, 1), Min: 44, Max: 50, Difference: 6},
+	}
+
+	tests := []struct {
+		name       string
+		args       string
+		stdin      string
+		wantErr    string
+		wantResult string
+		wantScales int
+	}{
+		{
+			name:    "validate",
+			args:    "validate",
+			wantErr: "2 problems found",
+			wantResult: `DATE        VERSION  PROBLEM
+2022-02-01  2        difference is 0, want 4
+2022-02-02  0        no version, run the migrations
+`,
+			wantScales: 3,
+		},
+		{
+			name: "migrate dry run",
+			args: "migrate -dry-run",
+			wantResult: `0001_versions: 1 readings
+dry run, nothing written
+`,
+			wantScales: 3,
+		},
+		{
+			name:       "dedupe",
+			args:       "dedupe",
+			wantResult: "dedupe changed 1 readings\n",
+			wantScales: 2,
+		},
+		{
+			name:       "recompute dry run",
+			args:       "recompute -dry-run",
+			wantResult: "recompute would change 1 readings\n",
+			wantScales: 3,
+		},
+		{
+			name:       "restore",
+			args:       "restore",
+			stdin:      `{"version":1,"created_at":"2022-02-01T07:00:00+07:00","scales":[{"date":"2022-02-01T00:00:00+07:00","min":45,"max":50,"difference":5,"version":1}]}`,
+			wantResult: "restored 1 readings\n",
+			wantScales: 1,
+		},
+		{
+			name:       "unknown command",
+			args:       "purge",
+			wantErr:    `unknown command "purge"`,
+			wantScales: 3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := scalerepo.NewScaleRepository()
+			repo.Load(ctx, &domain.ScaleSnapshot{Scales: scales})
+			open = func(storage string) (domain.ScaleRepository, error) {
+				return repo, nil
+			}
+			stdout := &bytes.Buffer{}
+
+			err := run(strings.Fields(test.args), strings.NewReader(test.stdin), stdout, &bytes.Buffer{})
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.wantResult, stdout.String())
+
+			got, _ := repo.Dump(ctx)
+			assert.Len(t, got.Scales, test.wantScales)
+		})
+	}
+}
+
+type closingRepository struct {
+	domain.ScaleRepository
+	closed bool
+}
+
+func (r *closingRepository) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestRunClose(t *testing.T) {
+	repo := &closingRepository{ScaleRepository: scalerepo.NewScaleRepository()}
+	open = func(storage string) (domain.ScaleRepository, error) {
+		return repo, nil
+	}
+	defer func() { open = openStorage }()
+
+	err := run([]string{"validate"}, nil, &bytes.Buffer{}, &bytes.Buffer{})
+	assert.NoError(t, err)
+	assert.True(t, repo.closed)
+}
+
+func TestOpenStorage(t *testing.T) {
+	for _, storage := range []string{"", scalerepo.StorageMemory} {
+		_, err := openStorage(storage)
+		assert.Equal(t, errMemoryStorage, err)
+	}
+
+	_, err := openStorage("postgres")
+	assert.EqualError(t, err, `unknown storage "postgres"`)
+
+	repo, err := openStorage(scalerepo.StorageFile + ":" + filepath.Join(t.TempDir(), "scales.log"))
+	assert.NoError(t, err)
+	assert.NotNil(t, repo)
+}
+
+func TestRunFile(t *testing.T) {
+	open = openStorage
+	storage := "-storage=" + scalerepo.StorageFile + ":" + filepath.Join(t.TempDir(), "scales.log")
+	backup := `{"version":1,"created_at":"2022-02-01T07:00:00+07:00","scales":[{"date":"2022-02-01T00:00:00+07:00","min":45,"max":50,"difference":5,"version":1}]}`
+
+	stdout := &bytes.Buffer{}
+	err := run([]string{storage, "restore"}, strings.NewReader(backup), stdout, &bytes.Buffer{})
+	assert.NoError(t, err)
+	assert.Equal(t, "restored 1 readings\n", stdout.String())
+
+	// a later run sees what the previous one wrote
+	stdout.Reset()
+	err = run([]string{storage, "validate"}, nil, stdout, &bytes.Buffer{})
+	assert.NoError(t, err)
+	assert.Equal(t, "1 readings are valid\n", stdout.String())
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// response is helper.HttpResponse with data left raw until its type is known.
+type response struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Errors  interface{}     `json:"errors"`
+}
+
+type apiError struct {
+	Code    int
+	Message string
+	Errors  interface{}
+}
+
+func (e *apiError) Error() string {
+	if e.Errors == nil {
+		return fmt.Sprintf("%s (%d)", e.Message, e.Code)
+	}
+	return fmt.Sprintf("%s (%d): %v", e.Message, e.Code, e.Errors)
+}
+
+// client talks to the v2 HTTP API.
+type client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+func newClient(baseURL, apiKey string) *client {
+	return &client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		http:    &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// do sends body as JSON and decodes the data of the response into out, the
+// response errors become an *apiError.
+func (c *client) do(method, path string, header http.Header, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set(common.HeaderAPIKey, c.apiKey)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	payload := &response{}
+	err = json.NewDecoder(res.Body).Decode(payload)
+	if err != nil {
+		return fmt.Errorf("%s %s: %s", method, path, res.Status)
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		// some failures still tell what went wrong in data, like batches
+		if out != nil && len(payload.Data) > 0 {
+			json.Unmarshal(payload.Data, out)
+		}
+		return &apiError{Code: res.StatusCode, Message: payload.Message, Errors: payload.Errors}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(payload.Data, out)
+}
+
+func ifMatch(version int) http.Header {
+	header := http.Header{}
+	if version > 0 {
+		header.Set(common.HeaderIfMatch, strconv.Quote(strconv.Itoa(version)))
+	}
+	return header
+}
+
+func (c *client) GetScales() (*domain.ScaleResponse, error) {
+	scales := &domain.ScaleResponse{}
+	err := c.do(http.MethodGet, "/v2/scales", nil, nil, scales)
+	if err != nil {
+		return nil, err
+	}
+	return scales, nil
+}
+
+func (c *client) GetScale(date string) ([]domain.Scale, error) {
+	var scales []domain.Scale
+	err := c.do(http.MethodGet, "/v2/scales/"+url.PathEscape(date), nil, nil, &scales)
+	if err != nil {
+		return nil, err
+	}
+	return scales, nil
+}
+
+func (c *client) Create(param *domain.ScaleParam) (*domain.Scale, error) {
+	scale := &domain.Scale{}
+	err := c.do(http.MethodPost, "/v2/scales", nil, param, scale)
+	if err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+// Update merges min and max, whichever are given, into the readings of date,
+// conditional on version unless it is 0.
+func (c *client) Update(date string, min, max *int, version int) (*domain.Scale, error) {
+	patch := map[string]int{}
+	if min != nil {
+		patch["min"] = *min
+	}
+	if max != nil {
+		patch["max"] = *max
+	}
+	header := ifMatch(version)
+	header.Set("Content-Type", common.MIMEApplicationMergePatchJSON)
+
+	scale := &domain.Scale{}
+	err := c.do(http.MethodPatch, "/v2/scales/"+url.PathEscape(date), header, patch, scale)
+	if err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+func (c *client) Delete(date string, version int) error {
+	return c.do(http.MethodDelete, "/v2/scales/"+url.PathEscape(date), ifMatch(version), nil, nil)
+}
+
+// Batch runs operations in one transaction, on failure the results, when
+// any, tell which operation failed.
+func (c *client) Batch(operations []domain.ScaleOperation) ([]domain.ScaleOperationResult, error) {
+	var results []domain.ScaleOperationResult
+	param := &domain.ScaleBatchParam{Operations: operations}
+	err := c.do(http.MethodPost, "/v2/scales/batch", nil, param, &results)
+	return results, err
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+)
+
+var csvHeader = []string{"date", "min", "max"}
+
+func writeCSV(w io.Writer, scales []domain.Scale) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(csvHeader)
+	if err != nil {
+		return err
+	}
+	for _, scale := range scales {
+		err = writer.Write([]string{
+			scale.Date.Format(common.TimeLayout),
+			strconv.Itoa(scale.Min),
+			strconv.Itoa(scale.Max),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// readCSV reads the date,min,max rows written by writeCSV as create
+// operations, the header is required and other columns are ignored.
+func readCSV(r io.Reader) ([]domain.ScaleOperation, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvHeader {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv: missing %s column", name)
+		}
+	}
+
+	var operations []domain.ScaleOperation
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return operations, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i := columns[name]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		date := field("date")
+		_, err = time.Parse(common.TimeLayout, date)
+		if err != nil {
+			return nil, fmt.Errorf("csv line %d: date %q is not %s", line, date, common.TimeLayout)
+		}
+		min, err := strconv.Atoi(field("min"))
+		if err != nil {
+			return nil, fmt.Errorf("csv line %d: min: %v", line, err)
+		}
+		max, err := strconv.Atoi(field("max"))
+		if err != nil {
+			return nil, fmt.Errorf("csv line %d: max: %v", line, err)
+		}
+
+		operations = append(operations, domain.ScaleOperation{
+			Op:   domain.ScaleOperationCreate,
+			Date: date,
+			Min:  min,
+			Max:  max,
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSV(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	buf := &bytes.Buffer{}
+	err := writeCSV(buf, []domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	got, err := readCSV(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ScaleOperation{
+		{Op: domain.ScaleOperationCreate, Date: "2022-02-01", Min: 45, Max: 50},
+	}, got)
+}
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       string
+		wantResult []domain.ScaleOperation
+		wantErr    string
+	}{
+		{
+			name: "columns in any order",
+			args: "max,note,date,min\n50,after run,2022-02-01,45\n",
+			wantResult: []domain.ScaleOperation{
+				{Op: domain.ScaleOperationCreate, Date: "2022-02-01", Min: 45, Max: 50},
+			},
+		},
+		{
+			name: "empty",
+			args: "",
+		},
+		{
+			name:    "missing column",
+			args:    "date,min\n2022-02-01,45\n",
+			wantErr: "csv: missing max column",
+		},
+		{
+			name:    "bad date",
+			args:    "date,min,max\n01/02/2022,45,50\n",
+			wantErr: `csv line 2: date "01/02/2022" is not 2006-01-02`,
+		},
+		{
+			name:    "bad weight",
+			args:    "date,min,max\n2022-02-01,45,\n",
+			wantErr: `csv line 2: max: strconv.Atoi: parsing "": invalid syntax`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := readCSV(strings.NewReader(test.args))
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantResult, got)
+		})
+	}
+}
@@ -0,0 +1,302 @@
+// Command scalectl reads and writes scale readings through the HTTP API.
+//
+//	scalectl [-url URL] [-api-key KEY] [-o table|json] <command> [flags]
+//
+// The base URL and API key default to SCALE_URL and SCALE_API_KEY.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/scale/src/domain"
+)
+
+const (
+	defaultURL = "http://localhost:8080"
+	// the most operations the API takes in one batch
+	batchSize = 100
+)
+
+type command struct {
+	usage string
+	run   func(c *client, p *printer, args []string, stdin io.Reader) error
+}
+
+var commands = map[string]command{
+	"list":    {"list the readings", list},
+	"show":    {"show the readings of a date", show},
+	"add":     {"add a reading", add},
+	"update":  {"update the readings of a date", update},
+	"delete":  {"delete the readings of a date", remove},
+	"average": {"print the average of the readings", average},
+	"trend":   {"print how the readings moved from one to the next", trendCommand},
+	"export":  {"write the readings as date,min,max CSV", export},
+	"import":  {"add the readings of a date,min,max CSV", importCSV},
+}
+
+var commandOrder = []string{"list", "show", "add", "update", "delete", "average", "trend", "export", "import"}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "scalectl:", err)
+		os.Exit(1)
+	}
+}
+
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("scalectl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	baseURL := fs.String("url", getenv("SCALE_URL", defaultURL), "base URL of the scale API")
+	apiKey := fs.String("api-key", os.Getenv("SCALE_API_KEY"), "API key sent as X-API-Key")
+	format := fs.String("o", formatTable, "output format, table or json")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: scalectl [flags] <command> [command flags]")
+		fmt.Fprintln(stderr, "\ncommands:")
+		for _, name := range commandOrder {
+			fmt.Fprintf(stderr, "  %-8s %s\n", name, commands[name].usage)
+		}
+		fmt.Fprintln(stderr, "\nflags:")
+		fs.PrintDefaults()
+	}
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+	p, err := newPrinter(stdout, *format)
+	if err != nil {
+		return err
+	}
+	return cmd.run(newClient(*baseURL, *apiKey), p, fs.Args()[1:], stdin)
+}
+
+// parse parses the flags of the command name, all of them required unless
+// listed as optional.
+func parse(fs *flag.FlagSet, args []string, optional ...string) error {
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	set := map[string]bool{}
+	for _, name := range optional {
+		set[name] = true
+	}
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	var missing error
+	fs.VisitAll(func(f *flag.Flag) {
+		if !set[f.Name] && missing == nil {
+			missing = fmt.Errorf("%s: -%s is required", fs.Name(), f.Name)
+		}
+	})
+	return missing
+}
+
+func list(c *client, p *printer, args []string, stdin io.Reader) error {
+	err := parse(flag.NewFlagSet("list", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+
+	scales, err := c.GetScales()
+	if err != nil {
+		return err
+	}
+	return p.Scales(scales.Scales)
+}
+
+func show(c *client, p *printer, args []string, stdin io.Reader) error {
+	fs := flag.NewFlagSet("show", flag.ContinueOnError)
+	date := fs.String("date", "", "date of the readings, YYYY-MM-DD")
+	err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	scales, err := c.GetScale(*date)
+	if err != nil {
+		return err
+	}
+	return p.Scales(scales)
+}
+
+func add(c *client, p *printer, args []string, stdin io.Reader) error {
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	date := fs.String("date", "", "date of the reading, YYYY-MM-DD")
+	min := fs.Int("min", 0, "min weight")
+	max := fs.Int("max", 0, "max weight")
+	err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	scale, err := c.Create(&domain.ScaleParam{Date: *date, Min: *min, Max: *max})
+	if err != nil {
+		return err
+	}
+	return p.Scales([]domain.Scale{*scale})
+}
+
+func update(c *client, p *printer, args []string, stdin io.Reader) error {
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	date := fs.String("date", "", "date of the readings, YYYY-MM-DD")
+	min := fs.Int("min", 0, "min weight, kept when not given")
+	max := fs.Int("max", 0, "max weight, kept when not given")
+	version := fs.Int("version", 0, "only update if the readings are still at this version")
+	err := parse(fs, args, "min", "max", "version")
+	if err != nil {
+		return err
+	}
+
+	var minValue, maxValue *int
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "min":
+			minValue = min
+		case "max":
+			maxValue = max
+		}
+	})
+	if minValue == nil && maxValue == nil {
+		return errors.New("update: -min or -max is required")
+	}
+
+	scale, err := c.Update(*date, minValue, maxValue, *version)
+	if err != nil {
+		return err
+	}
+	return p.Scales([]domain.Scale{*scale})
+}
+
+func remove(c *client, p *printer, args []string, stdin io.Reader) error {
+	fs := flag.NewFlagSet("delete", flag.ContinueOnError)
+	date := fs.String("date", "", "date of the readings, YYYY-MM-DD")
+	version := fs.Int("version", 0, "only delete if the readings are still at this version")
+	err := parse(fs, args, "version")
+	if err != nil {
+		return err
+	}
+
+	return c.Delete(*date, *version)
+}
+
+func average(c *client, p *printer, args []string, stdin io.Reader) error {
+	err := parse(flag.NewFlagSet("average", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+
+	scales, err := c.GetScales()
+	if err != nil {
+		return err
+	}
+	return p.Average(scales.Average)
+}
+
+func trendCommand(c *client, p *printer, args []string, stdin io.Reader) error {
+	err := parse(flag.NewFlagSet("trend", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+
+	scales, err := c.GetScales()
+	if err != nil {
+		return err
+	}
+	return p.Trend(trend(scales.Scales))
+}
+
+func export(c *client, p *printer, args []string, stdin io.Reader) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	file := fs.String("file", "-", "file to write, - for stdout")
+	err := parse(fs, args, "file")
+	if err != nil {
+		return err
+	}
+
+	scales, err := c.GetScales()
+	if err != nil {
+		return err
+	}
+	if *file == "-" {
+		return writeCSV(p.w, scales.Scales)
+	}
+
+	f, err := os.Create(*file)
+	if err != nil {
+		return err
+	}
+	err = writeCSV(f, scales.Scales)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// importCSV adds the readings in batches, each one all or nothing. When a
+// batch fails the ones before it stay imported.
+func importCSV(c *client, p *printer, args []string, stdin io.Reader) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := fs.String("file", "-", "file to read, - for stdin")
+	err := parse(fs, args, "file")
+	if err != nil {
+		return err
+	}
+
+	r := stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	operations, err := readCSV(r)
+	if err != nil {
+		return err
+	}
+
+	var results []domain.ScaleOperationResult
+	for start := 0; start < len(operations); start += batchSize {
+		end := start + batchSize
+		if end > len(operations) {
+			end = len(operations)
+		}
+
+		batch, err := c.Batch(operations[start:end])
+		results = append(results, batch...)
+		if err != nil {
+			p.Results(results)
+			return fmt.Errorf("import stopped after %d readings: %w", start, err)
+		}
+	}
+	return p.Results(results)
+}
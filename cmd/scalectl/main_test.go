@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/scale/src/scale/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	helper.InitTime()
+}
+
+func TestRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	// the handlers parse dates of requests in UTC
+	param := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+	scales := []domain.Scale{
+		{Date: date.AddDate(0, 0, 1), Min: 46, Max: 49, Difference: 3, Version: 1},
+		{Date: date, Min: 45, Max: 50, Difference: 5, Version: 2},
+	}
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+	e := echo.New()
+	handler.NewScaleHandler(e, scaleMock)
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		args       string
+		stdin      string
+		wantErr    string
+		wantResult string
+		mock       func()
+	}{
+		{
+			name: "list",
+			args: "list",
+			wantResult: `DATE        MIN  MAX  DIFFERENCE  VERSION
+2022-02-02  46   49   3           1
+2022-02-01  45   50   5           2
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(&domain.ScaleResponse{Scales: scales}, nil)
+			},
+		},
+		{
+			name: "show json",
+			args: "-o json show -date 2022-02-01",
+			wantResult: `[
+  {
+    "date": "2022-02-01T00:00:00+07:00",
+    "min": 45,
+    "max": 50,
+    "difference": 5,
+    "version": 2
+  }
+]
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return(scales[1:], nil)
+			},
+		},
+		{
+			name:    "show not found",
+			args:    "show -date 2022-02-03",
+			wantErr: "Failed get scale (404): " + domain.ErrNotFound.Error(),
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-03").Return(nil, nil)
+			},
+		},
+		{
+			name: "add",
+			args: "add -date 2022-02-01 -min 45 -max 50",
+			wantResult: `DATE        MIN  MAX  DIFFERENCE  VERSION
+2022-02-01  45   50   5           1
+`,
+			mock: func() {
+				scaleMock.EXPECT().Create(gomock.Any(), &domain.Scale{Date: param, Min: 45, Max: 50}).DoAndReturn(func(ctx interface{}, scale *domain.Scale) error {
+					scale.Difference = 5
+					scale.Version = 1
+					return nil
+				})
+			},
+		},
+		{
+			name:    "add missing flag",
+			args:    "add -date 2022-02-01 -min 45",
+			wantErr: "add: -max is required",
+			mock:    func() {},
+		},
+		{
+			name: "update",
+			args: "update -date 2022-02-01 -max 51 -version 2",
+			wantResult: `DATE        MIN  MAX  DIFFERENCE  VERSION
+2022-02-01  45   51   6           3
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return(scales[1:], nil)
+				scaleMock.EXPECT().Update(gomock.Any(), &domain.Scale{Date: param, Min: 45, Max: 51, Version: 2}).DoAndReturn(func(ctx interface{}, scale *domain.Scale) error {
+					scale.Difference = 6
+					scale.Version = 3
+					return nil
+				})
+			},
+		},
+		{
+			name:    "update stale",
+			args:    "update -date 2022-02-01 -min 44 -version 1",
+			wantErr: "Failed update scale (412): " + domain.ErrPreconditionFailed.Error(),
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return(scales[1:], nil)
+				scaleMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(domain.ErrPreconditionFailed)
+			},
+		},
+		{
+			name: "delete",
+			args: "delete -date 2022-02-01",
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return(scales[1:], nil)
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", 0).Return(nil)
+			},
+		},
+		{
+			name: "average",
+			args: "average",
+			wantResult: `MIN    MAX    DIFFERENCE
+45.50  49.50  4.00
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(&domain.ScaleResponse{
+					Scales:  scales,
+					Average: &domain.ScaleAverrage{Min: 45.5, Max: 49.5, Difference: 4},
+				}, nil)
+			},
+		},
+		{
+			name: "trend",
+			args: "trend",
+			wantResult: `DATE        MIN  MAX  MIN CHANGE  MAX CHANGE
+2022-02-01  45   50   0           0
+2022-02-02  46   49   +1          -1
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(&domain.ScaleResponse{Scales: scales}, nil)
+			},
+		},
+		{
+			name: "export",
+			args: "export",
+			wantResult: `date,min,max
+2022-02-02,46,49
+2022-02-01,45,50
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(&domain.ScaleResponse{Scales: scales}, nil)
+			},
+		},
+		{
+			name: "import",
+			args: "import",
+			stdin: `date,min,max
+2022-02-01,45,50
+`,
+			wantResult: `OP      DATE        STATUS  ERROR
+create  2022-02-01  201     
+`,
+			mock: func() {
+				scaleMock.EXPECT().Batch(gomock.Any(), []domain.ScaleOperation{
+					{Op: domain.ScaleOperationCreate, Date: "2022-02-01", Min: 45, Max: 50},
+				}).Return([]domain.ScaleOperationResult{
+					{Op: domain.ScaleOperationCreate, Date: "2022-02-01"},
+				}, nil)
+			},
+		},
+		{
+			name: "import failed",
+			args: "import",
+			stdin: `date,min,max
+2022-02-01,45,50
+2022-02-02,50,45
+`,
+			wantErr: "import stopped after 0 readings: Failed batch scales (400): " + domain.ErrBadParamInput.Error(),
+			wantResult: `OP      DATE        STATUS  ERROR
+create  2022-02-01  424     
+create  2022-02-02  400     ` + domain.ErrBadParamInput.Error() + `
+`,
+			mock: func() {
+				scaleMock.EXPECT().Batch(gomock.Any(), gomock.Any()).Return([]domain.ScaleOperationResult{
+					{Op: domain.ScaleOperationCreate, Date: "2022-02-01"},
+					{Op: domain.ScaleOperationCreate, Date: "2022-02-02", Error: domain.ErrBadParamInput.Error()},
+				}, domain.ErrBadParamInput)
+			},
+		},
+		{
+			name:    "unknown command",
+			args:    "purge",
+			wantErr: `unknown command "purge"`,
+			mock:    func() {},
+		},
+		{
+			name:    "unknown output",
+			args:    "-o yaml list",
+			wantErr: `unknown output "yaml", want table or json`,
+			mock:    func() {},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+			stdout := &bytes.Buffer{}
+			args := append([]string{"-url", server.URL}, strings.Fields(test.args)...)
+
+			err := run(args, strings.NewReader(test.stdin), stdout, &bytes.Buffer{})
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.wantResult, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// trendPoint is a reading with how much it moved since the one before it.
+type trendPoint struct {
+	Date      string `json:"date"`
+	Min       int    `json:"min"`
+	Max       int    `json:"max"`
+	MinChange int    `json:"min_change"`
+	MaxChange int    `json:"max_change"`
+}
+
+func trend(scales []domain.Scale) []trendPoint {
+	scales = append([]domain.Scale{}, scales...)
+	sort.SliceStable(scales, func(i, j int) bool {
+		return scales[i].Date.Before(scales[j].Date)
+	})
+
+	points := make([]trendPoint, 0, len(scales))
+	for i, scale := range scales {
+		point := trendPoint{
+			Date: scale.Date.Format(common.TimeLayout),
+			Min:  scale.Min,
+			Max:  scale.Max,
+		}
+		if i > 0 {
+			point.MinChange = scale.Min - scales[i-1].Min
+			point.MaxChange = scale.Max - scales[i-1].Max
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	if format != formatTable && format != formatJSON {
+		return nil, fmt.Errorf("unknown output %q, want %s or %s", format, formatTable, formatJSON)
+	}
+	return &printer{w: w, format: format}, nil
+}
+
+func (p *printer) json(v interface{}) error {
+	encoder := json.NewEncoder(p.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// table writes the tab separated rows aligned under header.
+func (p *printer) table(header string, rows []string) error {
+	w := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, header)
+	for _, row := range rows {
+		fmt.Fprintln(w, row)
+	}
+	return w.Flush()
+}
+
+func (p *printer) Scales(scales []domain.Scale) error {
+	if p.format == formatJSON {
+		return p.json(scales)
+	}
+	rows := make([]string, 0, len(scales))
+	for _, scale := range scales {
+		rows = append(rows, fmt.Sprintf("%s\t%d\t%d\t%d\t%d", scale.Date.Format(common.TimeLayout), scale.Min, scale.Max, scale.Difference, scale.Version))
+	}
+	return p.table("DATE\tMIN\tMAX\tDIFFERENCE\tVERSION", rows)
+}
+
+func (p *printer) Average(average *domain.ScaleAverrage) error {
+	if p.format == formatJSON {
+		return p.json(average)
+	}
+	if average == nil {
+		return p.table("MIN\tMAX\tDIFFERENCE", nil)
+	}
+	return p.table("MIN\tMAX\tDIFFERENCE", []string{
+		fmt.Sprintf("%.2f\t%.2f\t%.2f", average.Min, average.Max, average.Difference),
+	})
+}
+
+func signed(n int) string {
+	if n > 0 {
+		return "+" + strconv.Itoa(n)
+	}
+	return strconv.Itoa(n)
+}
+
+func (p *printer) Trend(points []trendPoint) error {
+	if p.format == formatJSON {
+		return p.json(points)
+	}
+	rows := make([]string, 0, len(points))
+	for _, point := range points {
+		rows = append(rows, fmt.Sprintf("%s\t%d\t%d\t%s\t%s", point.Date, point.Min, point.Max, signed(point.MinChange), signed(point.MaxChange)))
+	}
+	return p.table("DATE\tMIN\tMAX\tMIN CHANGE\tMAX CHANGE", rows)
+}
+
+func (p *printer) Results(results []domain.ScaleOperationResult) error {
+	if p.format == formatJSON {
+		return p.json(results)
+	}
+	rows := make([]string, 0, len(results))
+	for _, result := range results {
+		rows = append(rows, fmt.Sprintf("%s\t%s\t%d\t%s", result.Op, result.Date, result.Status, result.Error))
+	}
+	return p.table("OP\tDATE\tSTATUS\tERROR", rows)
+}
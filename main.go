@@ -1,76 +1,459 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/labstack/echo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	backuphandler "github.com/scale/src/backup/handler"
+	backupuc "github.com/scale/src/backup/usecase"
 	"github.com/scale/src/domain"
+	healthhandler "github.com/scale/src/health/handler"
+	healthuc "github.com/scale/src/health/usecase"
 	"github.com/scale/src/helper"
+	"github.com/scale/src/openapi"
 	"github.com/scale/src/scale/handler"
 	scalerepo "github.com/scale/src/scale/repository"
+	"github.com/scale/src/scale/stream"
 	scaleuc "github.com/scale/src/scale/usecase"
+	"github.com/scale/src/ui"
+	webhookhandler "github.com/scale/src/webhook/handler"
+	webhookrepo "github.com/scale/src/webhook/repository"
+	webhookuc "github.com/scale/src/webhook/usecase"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultTrashRetention = 30 * 24 * time.Hour
+	defaultShutdownDrain  = 5 * time.Second
+	defaultRateLimit      = 10
+	defaultRateLimitBurst = 20
+	defaultCORSMaxAge     = time.Hour
+	defaultRequestTimeout = 30 * time.Second
+	shutdownTimeout       = 10 * time.Second
 )
 
 var (
-	scaleUsecase    domain.ScaleUsecase
-	scaleRepository domain.ScaleRepository
+	logger            *logrus.Logger
+	scaleUsecase      domain.ScaleUsecase
+	scaleRepository   domain.ScaleRepository
+	storageRepository domain.ScaleRepository
+	historyRepository domain.ScaleHistoryRepository
+	scaleStream       domain.ScaleStream
+	webhookUsecase    domain.WebhookUsecase
+	webhookRepository domain.WebhookRepository
+	healthUsecase     domain.HealthUsecase
+	backupUsecase     domain.BackupUsecase
+	rateLimiter       *handler.RateLimiter
+	corsConfig        *handler.CORSConfig
+	requestTimeout    time.Duration
+	tlsCertFile       string
+	tlsKeyFile        string
+	seedData          bool
 )
 
 func init() {
 	helper.InitTime()
 }
 
-func initRepo() {
-	scaleRepository = scalerepo.NewScaleRepository()
+func initRepo() error {
+	setting := os.Getenv("SCALE_STORAGE")
+	backend, err := scalerepo.Open(setting, logger)
+	if err != nil {
+		return err
+	}
+	storageRepository = backend
+	// a persistent storage keeps what was there, seeding it again on every
+	// start would pile up readings
+	seedData = setting == "" || setting == scalerepo.StorageMemory
+	historyRepository = scalerepo.NewScaleHistoryRepository()
+	storage := scalerepo.NewTracingScaleRepository(scalerepo.NewMetricsScaleRepository(backend))
+	scaleRepository = scalerepo.NewAuditScaleRepository(storage, historyRepository)
+	// scrapes read the backend itself, they are neither measured nor traced
+	prometheus.MustRegister(scalerepo.NewScaleCollector(backend))
+	webhookRepository = webhookrepo.NewWebhookRepository()
+	return nil
 }
 
 func initUsecase() {
-	scaleUsecase = scaleuc.NewScaleUsecase(scaleRepository)
+	webhookUsecase = webhookuc.NewWebhookUsecase(webhookRepository, logger)
+	healthUsecase = healthuc.NewHealthUsecase(scaleRepository)
+	backupUsecase = backupuc.NewBackupUsecase(scaleRepository)
+	scaleStream = stream.NewScaleStream(stream.DefaultLogSize)
+	scaleUsecase = scaleuc.NewTracingScaleUsecase(scaleuc.NewScaleUsecase(scaleRepository, historyRepository, logger, webhookUsecase, scaleStream))
 
+	if !seedData {
+		return
+	}
 	// for init data
-	scaleUsecase.Create(&domain.Scale{
+	ctx := context.Background()
+	scaleUsecase.Create(ctx, &domain.Scale{
 		Date: time.Date(2018, 8, 22, 0, 0, 0, 0, helper.GetLocation()),
 		Min:  49,
 		Max:  50,
 	})
-	scaleUsecase.Create(&domain.Scale{
+	scaleUsecase.Create(ctx, &domain.Scale{
 		Date: time.Date(2018, 8, 21, 0, 0, 0, 0, helper.GetLocation()),
 		Min:  49,
 		Max:  49,
 	})
-	scaleUsecase.Create(&domain.Scale{
+	scaleUsecase.Create(ctx, &domain.Scale{
 		Date: time.Date(2018, 8, 20, 0, 0, 0, 0, helper.GetLocation()),
 		Min:  50,
 		Max:  52,
 	})
-	scaleUsecase.Create(&domain.Scale{
+	scaleUsecase.Create(ctx, &domain.Scale{
 		Date: time.Date(2018, 8, 19, 0, 0, 0, 0, helper.GetLocation()),
 		Min:  50,
 		Max:  51,
 	})
-	scaleUsecase.Create(&domain.Scale{
+	scaleUsecase.Create(ctx, &domain.Scale{
 		Date: time.Date(2018, 8, 18, 0, 0, 0, 0, helper.GetLocation()),
 		Min:  48,
 		Max:  50,
 	})
 }
 
-func initHTTP() error {
+func initHTTP() *echo.Echo {
 	e := echo.New()
 	e.Debug = true
+	e.HideBanner = true
+	e.HidePort = true
+	e.Use(handler.NewRequestLogger(logger))
+	e.Use(handler.Metrics)
+	e.Use(handler.Tracing)
+	e.Use(handler.SecurityHeaders(tlsCertFile != ""))
+	// before the limits, so browsers can read their errors
+	if corsConfig != nil {
+		e.Use(handler.CORS(*corsConfig))
+	}
+	if rateLimiter != nil {
+		e.Use(rateLimiter.RateLimit)
+	}
+	e.Use(handler.Actor)
+	if requestTimeout > 0 {
+		e.Use(handler.Timeout(requestTimeout))
+	}
 
 	handler.NewScaleHandler(e, scaleUsecase)
+	handler.NewScaleGraphQLHandler(e, scaleUsecase)
+	handler.NewScaleStreamHandler(e, scaleStream)
+	webhookhandler.NewWebhookHandler(e, webhookUsecase)
+	healthhandler.NewHealthHandler(e, healthUsecase)
+	// admin routes stay forbidden until ADMIN_TOKEN is set
+	backuphandler.NewBackupHandler(e, backupUsecase, os.Getenv("ADMIN_TOKEN"))
+	ui.NewUIHandler(e)
+	openapi.NewOpenAPIHandler(e)
 	e.GET("/ping", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, helper.Response(200, "Pong", nil, nil))
 	})
+	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+	// streams only end with their clients, shutdown would wait on them
+	e.Server.RegisterOnShutdown(scaleStream.Close)
+	e.TLSServer.RegisterOnShutdown(scaleStream.Close)
+	return e
+}
+
+func serveHTTP(e *echo.Echo) error {
+	var err error
+	if tlsCertFile != "" {
+		logger.WithFields(logrus.Fields{"address": ":8080", "tls": true}).Info("http server started")
+		err = e.StartTLS(":8080", tlsCertFile, tlsKeyFile)
+	} else {
+		logger.WithField("address", ":8080").Info("http server started")
+		err = e.Start(":8080")
+	}
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+func initGRPC() *grpc.Server {
+	interceptors := []grpc.UnaryServerInterceptor{handler.NewRequestLoggerInterceptor(logger)}
+	streamInterceptors := []grpc.StreamServerInterceptor{handler.NewRequestLoggerStreamInterceptor(logger)}
+	if rateLimiter != nil {
+		interceptors = append(interceptors, rateLimiter.RateLimitInterceptor)
+		streamInterceptors = append(streamInterceptors, rateLimiter.RateLimitStreamInterceptor)
+	}
+	interceptors = append(interceptors, handler.ActorInterceptor)
+	streamInterceptors = append(streamInterceptors, handler.ActorStreamInterceptor)
+	if requestTimeout > 0 {
+		interceptors = append(interceptors, handler.TimeoutInterceptor(requestTimeout))
+		streamInterceptors = append(streamInterceptors, handler.TimeoutStreamInterceptor(requestTimeout))
+	}
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+		grpc.MaxRecvMsgSize(handler.DefaultBodyLimit),
+	)
+	handler.NewScaleGRPCHandler(s, scaleUsecase)
+	return s
+}
+
+func serveGRPC(s *grpc.Server) error {
+	lis, err := net.Listen("tcp", ":9090")
+	if err != nil {
+		return err
+	}
+
+	logger.WithField("address", lis.Addr().String()).Info("grpc server started")
+	return s.Serve(lis)
+}
+
+// initTrashRetention keeps deleted readings in the trash for TRASH_RETENTION,
+// a positive duration such as 720h.
+func initTrashRetention() (time.Duration, error) {
+	value := os.Getenv("TRASH_RETENTION")
+	if value == "" {
+		return defaultTrashRetention, nil
+	}
+	retention, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	// 0 would empty the trash as soon as something is deleted
+	if retention <= 0 {
+		return 0, errors.New("TRASH_RETENTION must be positive")
+	}
+	return retention, nil
+}
+
+// purge permanently removes readings that stayed in the trash longer than
+// retention every hour.
+func purge(retention time.Duration) {
+	for {
+		err := scaleUsecase.Purge(context.Background(), retention)
+		if err != nil {
+			logger.WithError(err).Error("purge failed")
+		}
+		time.Sleep(time.Hour)
+	}
+}
 
-	return e.Start(":8080")
+// initRateLimiter limits every client to RATE_LIMIT requests per second with
+// bursts of RATE_LIMIT_BURST, a RATE_LIMIT of 0 turns it off. Clients are told
+// apart by their connection address, or by X-Forwarded-For and X-Real-IP when
+// TRUST_PROXY is true because a proxy in front sets them.
+func initRateLimiter() (*handler.RateLimiter, error) {
+	limit := float64(defaultRateLimit)
+	if value := os.Getenv("RATE_LIMIT"); value != "" {
+		var err error
+		limit, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	burst := defaultRateLimitBurst
+	if value := os.Getenv("RATE_LIMIT_BURST"); value != "" {
+		var err error
+		burst, err = strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var trustProxy bool
+	if value := os.Getenv("TRUST_PROXY"); value != "" {
+		var err error
+		trustProxy, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return handler.NewRateLimiter(limit, burst, trustProxy), nil
+}
+
+// initRequestTimeout gives every request REQUEST_TIMEOUT, a duration such as
+// 30s, to be served in, 0 turns it off. Event streams are left alone.
+func initRequestTimeout() (time.Duration, error) {
+	value := os.Getenv("REQUEST_TIMEOUT")
+	if value == "" {
+		return defaultRequestTimeout, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if timeout < 0 {
+		return 0, errors.New("REQUEST_TIMEOUT must not be negative")
+	}
+	return timeout, nil
+}
+
+// splitEnv returns the comma separated values of the environment variable key.
+func splitEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// initCORS allows browser scripts from CORS_ALLOW_ORIGINS, none by default or
+// * for any, with CORS_ALLOW_METHODS, CORS_ALLOW_HEADERS and
+// CORS_ALLOW_CREDENTIALS.
+func initCORS() (*handler.CORSConfig, error) {
+	origins := splitEnv("CORS_ALLOW_ORIGINS")
+	if len(origins) == 0 {
+		return nil, nil
+	}
+
+	config := &handler.CORSConfig{
+		AllowOrigins: origins,
+		AllowMethods: handler.DefaultCORSMethods,
+		AllowHeaders: handler.DefaultCORSHeaders,
+		MaxAge:       defaultCORSMaxAge,
+	}
+	if methods := splitEnv("CORS_ALLOW_METHODS"); len(methods) > 0 {
+		config.AllowMethods = methods
+	}
+	if headers := splitEnv("CORS_ALLOW_HEADERS"); len(headers) > 0 {
+		config.AllowHeaders = headers
+	}
+	if value := os.Getenv("CORS_ALLOW_CREDENTIALS"); value != "" {
+		var err error
+		config.AllowCredentials, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, origin := range origins {
+		if origin == "*" && config.AllowCredentials {
+			return nil, errors.New("CORS_ALLOW_CREDENTIALS needs the origins listed, not *")
+		}
+	}
+	return config, nil
+}
+
+// shutdown waits for SIGINT or SIGTERM, then keeps serving with /readyz
+// failing for SHUTDOWN_DRAIN, a duration such as 5s, so load balancers stop
+// routing here before the servers finish their requests and stop. Webhook
+// deliveries in flight get what is left of the shutdown timeout.
+func shutdown(drain time.Duration, e *echo.Echo, s *grpc.Server) error {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-quit
+
+	logger.WithFields(logrus.Fields{
+		"signal": sig.String(),
+		"drain":  drain.String(),
+	}).Info("draining")
+	healthUsecase.Drain()
+	time.Sleep(drain)
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	err := e.Shutdown(ctx)
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.Stop()
+	}
+	if err != nil {
+		return err
+	}
+	return webhookUsecase.Close(ctx)
 }
 
 func main() {
-	initRepo()
+	var err error
+	logger, err = helper.NewLogger(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	drain := defaultShutdownDrain
+	if value := os.Getenv("SHUTDOWN_DRAIN"); value != "" {
+		drain, err = time.ParseDuration(value)
+		if err != nil {
+			logger.Fatal(err)
+		}
+	}
+	shutdownTracer, err := helper.InitTracer(os.Getenv("OTEL_TRACES_EXPORTER"))
+	if err != nil {
+		logger.Fatal(err)
+	}
+	defer shutdownTracer(context.Background())
+
+	rateLimiter, err = initRateLimiter()
+	if err != nil {
+		logger.Fatal(err)
+	}
+	corsConfig, err = initCORS()
+	if err != nil {
+		logger.Fatal(err)
+	}
+	requestTimeout, err = initRequestTimeout()
+	if err != nil {
+		logger.Fatal(err)
+	}
+	retention, err := initTrashRetention()
+	if err != nil {
+		logger.Fatal(err)
+	}
+	// HTTPS when both are given
+	tlsCertFile, tlsKeyFile = os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE")
+	if (tlsCertFile == "") != (tlsKeyFile == "") {
+		logger.Fatal("TLS_CERT_FILE and TLS_KEY_FILE must be given together")
+	}
+
+	err = initRepo()
+	if err != nil {
+		logger.Fatal(err)
+	}
 	initUsecase()
 
-	initHTTP()
+	e := initHTTP()
+	s := initGRPC()
+	go func() {
+		err := serveHTTP(e)
+		if err != nil {
+			logger.Fatal(err)
+		}
+	}()
+	go func() {
+		err := serveGRPC(s)
+		if err != nil {
+			logger.Fatal(err)
+		}
+	}()
+	go purge(retention)
+
+	err = shutdown(drain, e, s)
+	if err != nil {
+		logger.WithError(err).Error("server did not stop in time")
+		return
+	}
+	// persistent storages flush and release their files and locks
+	if closer, ok := storageRepository.(io.Closer); ok {
+		err = closer.Close()
+		if err != nil {
+			logger.WithError(err).Error("storage not closed")
+		}
+	}
+	logger.Info("server stopped")
 }
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/scale/src/scale/maintenance"
+)
+
+// a restore carries every reading at once
+const restoreBodyLimit = 64 << 20
+
+type backupHandler struct {
+	backupUsecase domain.BackupUsecase
+}
+
+// NewBackupHandler serves backups to callers bearing adminToken, with no
+// token every admin route is forbidden.
+func NewBackupHandler(e *echo.Echo, backupUsecase domain.BackupUsecase, adminToken string) {
+	handler := &backupHandler{
+		backupUsecase: backupUsecase,
+	}
+
+	r := e.Group("/admin", AdminAuth(adminToken), helper.BodyLimit(restoreBodyLimit))
+	r.POST("/backup", handler.Backup)
+	r.POST("/restore", handler.Restore)
+}
+
+// AdminAuth lets through requests with "Authorization: Bearer <token>",
+// compared in constant time.
+func AdminAuth(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if token == "" {
+				code := http.StatusForbidden
+				return c.JSON(code, helper.Response(code, "Forbidden", nil, "admin routes are disabled"))
+			}
+			auth := c.Request().Header.Get(echo.HeaderAuthorization)
+			given := strings.TrimPrefix(auth, "Bearer ")
+			if given == auth || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="admin"`)
+				code := http.StatusUnauthorized
+				return c.JSON(code, helper.Response(code, "Unauthorized", nil, "invalid admin token"))
+			}
+			return next(c)
+		}
+	}
+}
+
+// Backup answers the backup itself rather than an envelope, so the body can
+// be saved and posted back to Restore as is.
+func (h *backupHandler) Backup(c echo.Context) error {
+	backup, err := h.backupUsecase.Backup(c.Request().Context())
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed backup", nil, err.Error()))
+	}
+
+	filename := fmt.Sprintf("scale-%s.json", backup.CreatedAt.Format("20060102T150405"))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	c.Response().WriteHeader(http.StatusOK)
+	return maintenance.WriteBackup(c.Response(), backup)
+}
+
+func (h *backupHandler) Restore(c echo.Context) error {
+	backup, err := maintenance.ReadBackup(c.Request().Body)
+	if err != nil {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed restore backup", nil, err.Error()))
+	}
+
+	err = h.backupUsecase.Restore(c.Request().Context(), backup)
+	if invalid, ok := err.(*maintenance.InvalidBackupError); ok {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed restore backup", invalid.Problems, err.Error()))
+	}
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed restore backup", nil, err.Error()))
+	}
+
+	data := helper.Response(200, "Success restore backup", nil, nil)
+	return c.JSON(http.StatusOK, data)
+}
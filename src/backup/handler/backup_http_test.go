@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/scale/src/scale/maintenance"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	helper.InitTime()
+}
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		args       string
+		wantCode   int
+		wantResult string
+	}{
+		{
+			name:       "success",
+			token:      "secret",
+			args:       "Bearer secret",
+			wantCode:   http.StatusOK,
+			wantResult: "ok",
+		},
+		{
+			name:     "disabled",
+			args:     "Bearer ",
+			wantCode: http.StatusForbidden,
+			wantResult: `{"code":403,"message":"Forbidden","data":null,"errors":"admin routes are disabled"}
+`,
+		},
+		{
+			name:     "wrong token",
+			token:    "secret",
+			args:     "Bearer guess",
+			wantCode: http.StatusUnauthorized,
+			wantResult: `{"code":401,"message":"Unauthorized","data":null,"errors":"invalid admin token"}
+`,
+		},
+		{
+			name:     "not bearer",
+			token:    "secret",
+			args:     "secret",
+			wantCode: http.StatusUnauthorized,
+			wantResult: `{"code":401,"message":"Unauthorized","data":null,"errors":"invalid admin token"}
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/admin/backup", nil)
+			req.Header.Set(echo.HeaderAuthorization, test.args)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := AdminAuth(test.token)(func(c echo.Context) error {
+				return c.String(http.StatusOK, "ok")
+			})(c)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantCode, rec.Code)
+			assert.Equal(t, test.wantResult, rec.Body.String())
+			if test.wantCode == http.StatusUnauthorized {
+				assert.Equal(t, `Bearer realm="admin"`, rec.Header().Get(echo.HeaderWWWAuthenticate))
+			}
+		})
+	}
+}
+
+func TestBackup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	backupMock := mock_domain.NewMockBackupUsecase(ctrl)
+	createdAt := time.Date(2022, 2, 1, 7, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		wantCode   int
+		wantResult string
+		mock       func()
+	}{
+		{
+			name:     "success",
+			wantCode: http.StatusOK,
+			wantResult: `{
+  "version": 2,
+  "created_at": "2022-02-01T07:00:00Z",
+  "checksum": "sha256:abc",
+  "snapshot": {
+    "scales": [],
+    "history": []
+  }
+}
+`,
+			mock: func() {
+				backupMock.EXPECT().Backup(gomock.Any()).Return(&domain.ScaleBackup{
+					Version:   domain.ScaleBackupVersion,
+					CreatedAt: createdAt,
+					Checksum:  "sha256:abc",
+					Snapshot:  &domain.ScaleSnapshot{Scales: []domain.Scale{}, History: []domain.ScaleHistory{}},
+				}, nil)
+			},
+		},
+		{
+			name:     "failed",
+			wantCode: http.StatusInternalServerError,
+			wantResult: `{"code":500,"message":"Failed backup","data":null,"errors":"unexpected"}
+`,
+			mock: func() {
+				backupMock.EXPECT().Backup(gomock.Any()).Return(nil, errors.New("unexpected"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/admin/backup", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			h := backupHandler{backupUsecase: backupMock}
+
+			if assert.NoError(t, h.Backup(c)) {
+				assert.Equal(t, test.wantCode, rec.Code)
+				assert.Equal(t, test.wantResult, rec.Body.String())
+			}
+			if test.wantCode == http.StatusOK {
+				assert.Equal(t, `attachment; filename="scale-20220201T070000.json"`, rec.Header().Get(echo.HeaderContentDisposition))
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	backupMock := mock_domain.NewMockBackupUsecase(ctrl)
+	backup := `{"version":1,"created_at":"2022-02-01T07:00:00Z","scales":[]}`
+
+	tests := []struct {
+		name       string
+		args       string
+		wantCode   int
+		wantResult string
+		mock       func()
+	}{
+		{
+			name:     "success",
+			args:     backup,
+			wantCode: http.StatusOK,
+			wantResult: `{"code":200,"message":"Success restore backup","data":null,"errors":null}
+`,
+			mock: func() {
+				backupMock.EXPECT().Restore(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:     "unreadable",
+			args:     `{"version":3}`,
+			wantCode: http.StatusBadRequest,
+			wantResult: `{"code":400,"message":"Failed restore backup","data":null,"errors":"backup version 3 is not supported, want at most 2"}
+`,
+			mock: func() {},
+		},
+		{
+			name:     "invalid",
+			args:     backup,
+			wantCode: http.StatusBadRequest,
+			wantResult: `{"code":400,"message":"Failed restore backup","data":[{"date":"2022-02-01","version":1,"reason":"max 45 is below min 50"}],"errors":"backup has 1 problems"}
+`,
+			mock: func() {
+				backupMock.EXPECT().Restore(gomock.Any(), gomock.Any()).Return(&maintenance.InvalidBackupError{Problems: []maintenance.Problem{
+					{Date: "2022-02-01", Version: 1, Reason: "max 45 is below min 50"},
+				}})
+			},
+		},
+		{
+			name:     "failed",
+			args:     backup,
+			wantCode: http.StatusInternalServerError,
+			wantResult: `{"code":500,"message":"Failed restore backup","data":null,"errors":"unexpected"}
+`,
+			mock: func() {
+				backupMock.EXPECT().Restore(gomock.Any(), gomock.Any()).Return(errors.New("unexpected"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/admin/restore", strings.NewReader(test.args))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			h := backupHandler{backupUsecase: backupMock}
+
+			if assert.NoError(t, h.Restore(c)) {
+				assert.Equal(t, test.wantCode, rec.Code)
+				assert.Equal(t, test.wantResult, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRestoreBodyLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	backupMock := mock_domain.NewMockBackupUsecase(ctrl)
+	e := echo.New()
+	NewBackupHandler(e, backupMock, "secret")
+	backup := `{"version":1,"created_at":"2022-02-01T07:00:00Z","scales":[]}`
+
+	tests := []struct {
+		name          string
+		args          string
+		contentLength int64
+		wantCode      int
+		mock          func()
+	}{
+		{
+			name:     "larger than batches",
+			args:     backup + strings.Repeat(" ", 2<<20),
+			wantCode: http.StatusOK,
+			mock: func() {
+				backupMock.EXPECT().Restore(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:          "over its own limit",
+			args:          backup,
+			contentLength: restoreBodyLimit + 1,
+			wantCode:      http.StatusRequestEntityTooLarge,
+			mock:          func() {},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+			req := httptest.NewRequest(http.MethodPost, "/admin/restore", strings.NewReader(test.args))
+			req.Header.Set(echo.HeaderAuthorization, "Bearer secret")
+			if test.contentLength != 0 {
+				req.ContentLength = test.contentLength
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.wantCode, rec.Code)
+		})
+	}
+}
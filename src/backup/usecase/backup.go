@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/scale/src/domain"
+	"github.com/scale/src/scale/maintenance"
+)
+
+type backupUsecase struct {
+	scaleRepository domain.ScaleRepository
+}
+
+// NewBackupUsecase backs up and restores through scaleRepository as given,
+// pass it the audit decorator so history comes along.
+func NewBackupUsecase(scaleRepository domain.ScaleRepository) domain.BackupUsecase {
+	return &backupUsecase{
+		scaleRepository: scaleRepository,
+	}
+}
+
+func (b *backupUsecase) Backup(ctx context.Context) (*domain.ScaleBackup, error) {
+	return maintenance.Backup(ctx, b.scaleRepository)
+}
+
+func (b *backupUsecase) Restore(ctx context.Context, backup *domain.ScaleBackup) error {
+	return maintenance.Restore(ctx, b.scaleRepository, backup)
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/scale/src/scale/maintenance"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	helper.InitTime()
+}
+
+func TestBackup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaleMock := mock_domain.NewMockScaleRepository(ctrl)
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	snapshot := &domain.ScaleSnapshot{
+		Scales: []domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1}},
+	}
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			mock: func() {
+				scaleMock.EXPECT().Dump(gomock.Any()).Return(snapshot, nil)
+			},
+		},
+		{
+			name:    "failed",
+			wantErr: errors.New("unexpected"),
+			mock: func() {
+				scaleMock.EXPECT().Dump(gomock.Any()).Return(nil, errors.New("unexpected"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+			b := NewBackupUsecase(scaleMock)
+			got, err := b.Backup(context.Background())
+			assert.Equal(t, test.wantErr, err)
+			if test.wantErr == nil {
+				assert.Equal(t, domain.ScaleBackupVersion, got.Version)
+				assert.Equal(t, snapshot, got.Snapshot)
+				assert.NotEmpty(t, got.Checksum)
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaleMock := mock_domain.NewMockScaleRepository(ctrl)
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+
+	tests := []struct {
+		name    string
+		args    []domain.Scale
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			args: []domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5}},
+			mock: func() {
+				scaleMock.EXPECT().Load(gomock.Any(), &domain.ScaleSnapshot{
+					Scales: []domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1}},
+				}).Return(nil)
+			},
+		},
+		{
+			name: "invalid",
+			args: []domain.Scale{{Date: date, Min: 50, Max: 45, Difference: -5, Version: 1}},
+			wantErr: &maintenance.InvalidBackupError{Problems: []maintenance.Problem{
+				{Date: "2022-02-01", Version: 1, Reason: "max 45 is below min 50"},
+			}},
+			mock: func() {},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+			backup, _ := maintenance.NewBackup(&domain.ScaleSnapshot{Scales: test.args})
+			b := NewBackupUsecase(scaleMock)
+			err := b.Restore(context.Background(), backup)
+			assert.Equal(t, test.wantErr, err)
+		})
+	}
+}
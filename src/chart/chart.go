@@ -0,0 +1,228 @@
+package chart
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+)
+
+const (
+	defaultWidth  = 800
+	defaultHeight = 400
+
+	paddingLeft   = 50
+	paddingRight  = 20
+	paddingTop    = 20
+	paddingBottom = 40
+
+	maxYTicks = 20
+)
+
+type Options struct {
+	From          time.Time
+	To            time.Time
+	Width         int
+	Height        int
+	MovingAverage int
+	Goal          *float64
+}
+
+type Point struct {
+	X float64
+	Y float64
+}
+
+type Tick struct {
+	Pos   float64
+	Label string
+}
+
+// Layout holds every series already projected into chart coordinates so each
+// renderer only has to draw it.
+type Layout struct {
+	Width      int
+	Height     int
+	Left       float64
+	Right      float64
+	Top        float64
+	Bottom     float64
+	Min        []Point
+	Max        []Point
+	MinAverage []Point
+	MaxAverage []Point
+	Goal       *float64
+	XTicks     []Tick
+	YTicks     []Tick
+
+	low   float64
+	high  float64
+	first time.Time
+	span  time.Duration
+}
+
+func (l *Layout) Empty() bool {
+	return len(l.Min) == 0
+}
+
+// Filter keeps the scales whose day falls inside [from, to], ignoring zero
+// bounds, ordered from the oldest reading.
+func Filter(scales []domain.Scale, from, to time.Time) []domain.Scale {
+	result := []domain.Scale{}
+	for _, scale := range scales {
+		day := scale.Date.Format(common.TimeLayout)
+		if !from.IsZero() && day < from.Format(common.TimeLayout) {
+			continue
+		}
+		if !to.IsZero() && day > to.Format(common.TimeLayout) {
+			continue
+		}
+		result = append(result, scale)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+	return result
+}
+
+func MovingAverage(values []float64, window int) []float64 {
+	if window <= 1 || window > len(values) {
+		return nil
+	}
+
+	result := make([]float64, 0, len(values)-window+1)
+	var sum float64
+	for i, value := range values {
+		sum += value
+		if i >= window {
+			sum -= values[i-window]
+		}
+		if i >= window-1 {
+			result = append(result, sum/float64(window))
+		}
+	}
+	return result
+}
+
+func NewLayout(scales []domain.Scale, opt Options) *Layout {
+	width, height := opt.Width, opt.Height
+	if width <= 0 {
+		width = defaultWidth
+	}
+	if height <= 0 {
+		height = defaultHeight
+	}
+
+	layout := &Layout{
+		Width:  width,
+		Height: height,
+		Left:   paddingLeft,
+		Right:  float64(width - paddingRight),
+		Top:    paddingTop,
+		Bottom: float64(height - paddingBottom),
+		Goal:   opt.Goal,
+	}
+
+	scales = Filter(scales, opt.From, opt.To)
+	if len(scales) == 0 {
+		return layout
+	}
+
+	mins := make([]float64, len(scales))
+	maxs := make([]float64, len(scales))
+	low, high := math.Inf(1), math.Inf(-1)
+	for i, scale := range scales {
+		mins[i] = float64(scale.Min)
+		maxs[i] = float64(scale.Max)
+		low = math.Min(low, mins[i])
+		high = math.Max(high, maxs[i])
+	}
+	if opt.Goal != nil {
+		low = math.Min(low, *opt.Goal)
+		high = math.Max(high, *opt.Goal)
+	}
+
+	step := niceStep((high - low) / 5)
+	low = math.Floor(low/step)*step - step
+	high = math.Ceil(high/step)*step + step
+
+	layout.low, layout.high = low, high
+	layout.first = scales[0].Date
+	layout.span = scales[len(scales)-1].Date.Sub(layout.first)
+	x, y := layout.X, layout.Y
+
+	for i, scale := range scales {
+		layout.Min = append(layout.Min, Point{X: x(scale.Date), Y: y(mins[i])})
+		layout.Max = append(layout.Max, Point{X: x(scale.Date), Y: y(maxs[i])})
+	}
+
+	window := opt.MovingAverage
+	for i, value := range MovingAverage(mins, window) {
+		layout.MinAverage = append(layout.MinAverage, Point{X: x(scales[i+window-1].Date), Y: y(value)})
+	}
+	for i, value := range MovingAverage(maxs, window) {
+		layout.MaxAverage = append(layout.MaxAverage, Point{X: x(scales[i+window-1].Date), Y: y(value)})
+	}
+
+	// counted rather than stepped, adding step to a large enough value no
+	// longer changes it
+	ticks := int(math.Round((high - low) / step))
+	if ticks > maxYTicks {
+		ticks = maxYTicks
+	}
+	for i := 0; i <= ticks; i++ {
+		value := low + float64(i)*step
+		layout.YTicks = append(layout.YTicks, Tick{Pos: y(value), Label: formatValue(value)})
+	}
+
+	labels := 6
+	if len(scales) < labels {
+		labels = len(scales)
+	}
+	for i := 0; i < labels; i++ {
+		index := 0
+		if labels > 1 {
+			index = i * (len(scales) - 1) / (labels - 1)
+		}
+		date := scales[index].Date
+		layout.XTicks = append(layout.XTicks, Tick{Pos: x(date), Label: date.Format(common.TimeLayout)})
+	}
+
+	return layout
+}
+
+func (l *Layout) X(date time.Time) float64 {
+	if l.span == 0 {
+		return (l.Left + l.Right) / 2
+	}
+	return l.Left + float64(date.Sub(l.first))/float64(l.span)*(l.Right-l.Left)
+}
+
+func (l *Layout) Y(value float64) float64 {
+	if l.high == l.low {
+		return (l.Top + l.Bottom) / 2
+	}
+	return l.Bottom - (value-l.low)/(l.high-l.low)*(l.Bottom-l.Top)
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func niceStep(raw float64) float64 {
+	if raw <= 0 {
+		return 1
+	}
+
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, factor := range []float64{1, 2, 5, 10} {
+		if raw <= factor*magnitude {
+			return math.Max(factor*magnitude, 1)
+		}
+	}
+	return 10 * magnitude
+}
@@ -0,0 +1,193 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	helper.InitTime()
+}
+
+func TestFilter(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	scales := []domain.Scale{
+		{Date: date.AddDate(0, 0, 2), Min: 47, Max: 50},
+		{Date: date.AddDate(0, 0, 1), Min: 46, Max: 50},
+		{Date: date, Min: 45, Max: 50},
+	}
+
+	type args struct {
+		from time.Time
+		to   time.Time
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantResult []domain.Scale
+	}{
+		{
+			name: "no range",
+			args: args{},
+			wantResult: []domain.Scale{
+				{Date: date, Min: 45, Max: 50},
+				{Date: date.AddDate(0, 0, 1), Min: 46, Max: 50},
+				{Date: date.AddDate(0, 0, 2), Min: 47, Max: 50},
+			},
+		},
+		{
+			name: "inclusive range",
+			args: args{
+				from: time.Date(2022, 2, 2, 0, 0, 0, 0, time.UTC),
+				to:   time.Date(2022, 2, 3, 0, 0, 0, 0, time.UTC),
+			},
+			wantResult: []domain.Scale{
+				{Date: date.AddDate(0, 0, 1), Min: 46, Max: 50},
+				{Date: date.AddDate(0, 0, 2), Min: 47, Max: 50},
+			},
+		},
+		{
+			name: "empty range",
+			args: args{
+				from: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantResult: []domain.Scale{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Filter(scales, test.args.from, test.args.to)
+			assert.Equal(t, test.wantResult, got)
+		})
+	}
+}
+
+func TestMovingAverage(t *testing.T) {
+	type args struct {
+		values []float64
+		window int
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantResult []float64
+	}{
+		{
+			name: "success",
+			args: args{
+				values: []float64{1, 2, 3, 4, 5},
+				window: 3,
+			},
+			wantResult: []float64{2, 3, 4},
+		},
+		{
+			name: "disabled",
+			args: args{
+				values: []float64{1, 2, 3},
+				window: 0,
+			},
+			wantResult: nil,
+		},
+		{
+			name: "window too large",
+			args: args{
+				values: []float64{1, 2, 3},
+				window: 4,
+			},
+			wantResult: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := MovingAverage(test.args.values, test.args.window)
+			assert.Equal(t, test.wantResult, got)
+		})
+	}
+}
+
+func TestSVG(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	goal := 44.5
+	scales := []domain.Scale{
+		{Date: date.AddDate(0, 0, 2), Min: 47, Max: 50},
+		{Date: date.AddDate(0, 0, 1), Min: 46, Max: 50},
+		{Date: date, Min: 45, Max: 50},
+	}
+
+	type args struct {
+		scales []domain.Scale
+		opt    Options
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantResult []string
+		notResult  []string
+	}{
+		{
+			name: "success",
+			args: args{
+				scales: scales,
+			},
+			wantResult: []string{`class="difference"`, `class="min"`, `class="max"`, "2022-02-01", "2022-02-03"},
+			notResult:  []string{`class="goal"`, `class="min-average"`},
+		},
+		{
+			name: "overlays",
+			args: args{
+				scales: scales,
+				opt: Options{
+					MovingAverage: 2,
+					Goal:          &goal,
+				},
+			},
+			wantResult: []string{`class="goal"`, "goal 44.5", `class="min-average"`, `class="max-average"`},
+		},
+		{
+			name: "no data",
+			args: args{
+				scales: scales,
+				opt: Options{
+					From: date.AddDate(0, 1, 0),
+				},
+			},
+			wantResult: []string{"No data"},
+			notResult:  []string{`class="min"`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := string(SVG(test.args.scales, test.args.opt))
+			assert.True(t, strings.HasPrefix(got, "<svg "))
+			assert.True(t, strings.HasSuffix(got, "</svg>"))
+			for _, want := range test.wantResult {
+				assert.Contains(t, got, want)
+			}
+			for _, not := range test.notResult {
+				assert.NotContains(t, got, not)
+			}
+		})
+	}
+}
+
+func TestNewLayoutLargeValues(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	scales := []domain.Scale{
+		{Date: date, Min: 1e17, Max: 1e17 + 1},
+		{Date: date.AddDate(0, 0, 1), Min: 4e18, Max: 9e18},
+	}
+
+	for _, scale := range scales {
+		layout := NewLayout([]domain.Scale{scale}, Options{})
+		assert.NotEmpty(t, layout.YTicks)
+		assert.LessOrEqual(t, len(layout.YTicks), maxYTicks+1)
+	}
+	layout := NewLayout(scales, Options{})
+	assert.NotEmpty(t, layout.YTicks)
+	assert.LessOrEqual(t, len(layout.YTicks), maxYTicks+1)
+}
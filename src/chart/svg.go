@@ -0,0 +1,68 @@
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/scale/src/domain"
+)
+
+func SVG(scales []domain.Scale, opt Options) []byte {
+	layout := NewLayout(scales, opt)
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`,
+		layout.Width, layout.Height, layout.Width, layout.Height)
+	fmt.Fprintf(buf, `<rect width="%d" height="%d" fill="#ffffff"/>`, layout.Width, layout.Height)
+
+	if layout.Empty() {
+		fmt.Fprintf(buf, `<text x="%d" y="%d" text-anchor="middle" fill="#666666">No data</text>`, layout.Width/2, layout.Height/2)
+		buf.WriteString(`</svg>`)
+		return buf.Bytes()
+	}
+
+	for _, tick := range layout.YTicks {
+		fmt.Fprintf(buf, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#eeeeee"/>`, layout.Left, tick.Pos, layout.Right, tick.Pos)
+		fmt.Fprintf(buf, `<text x="%.1f" y="%.1f" text-anchor="end" fill="#666666">%s</text>`, layout.Left-6, tick.Pos+4, html.EscapeString(tick.Label))
+	}
+	for _, tick := range layout.XTicks {
+		fmt.Fprintf(buf, `<text x="%.1f" y="%.1f" text-anchor="middle" fill="#666666">%s</text>`, tick.Pos, layout.Bottom+18, html.EscapeString(tick.Label))
+	}
+	fmt.Fprintf(buf, `<path d="M%.1f %.1fV%.1fH%.1f" fill="none" stroke="#999999"/>`, layout.Left, layout.Top, layout.Bottom, layout.Right)
+
+	band := append(append([]Point{}, layout.Max...), reverse(layout.Min)...)
+	fmt.Fprintf(buf, `<polygon class="difference" points="%s" fill="#4e79a7" fill-opacity="0.2"/>`, points(band))
+	fmt.Fprintf(buf, `<polyline class="max" points="%s" fill="none" stroke="#e15759" stroke-width="2"/>`, points(layout.Max))
+	fmt.Fprintf(buf, `<polyline class="min" points="%s" fill="none" stroke="#4e79a7" stroke-width="2"/>`, points(layout.Min))
+
+	if len(layout.MaxAverage) > 0 {
+		fmt.Fprintf(buf, `<polyline class="max-average" points="%s" fill="none" stroke="#e15759" stroke-dasharray="4 3"/>`, points(layout.MaxAverage))
+		fmt.Fprintf(buf, `<polyline class="min-average" points="%s" fill="none" stroke="#4e79a7" stroke-dasharray="4 3"/>`, points(layout.MinAverage))
+	}
+	if layout.Goal != nil {
+		y := layout.Y(*layout.Goal)
+		fmt.Fprintf(buf, `<line class="goal" x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#59a14f" stroke-dasharray="6 4"/>`, layout.Left, y, layout.Right, y)
+		fmt.Fprintf(buf, `<text x="%.1f" y="%.1f" text-anchor="end" fill="#59a14f">goal %s</text>`, layout.Right, y-4, formatValue(*layout.Goal))
+	}
+
+	buf.WriteString(`</svg>`)
+	return buf.Bytes()
+}
+
+func points(ps []Point) string {
+	coords := make([]string, len(ps))
+	for i, p := range ps {
+		coords[i] = fmt.Sprintf("%.1f,%.1f", p.X, p.Y)
+	}
+	return strings.Join(coords, " ")
+}
+
+func reverse(ps []Point) []Point {
+	result := make([]Point, len(ps))
+	for i, p := range ps {
+		result[len(ps)-1-i] = p
+	}
+	return result
+}
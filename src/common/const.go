@@ -1,5 +1,14 @@
 package common
 
 const (
-	TimeLayout = "2006-01-02"
+	TimeLayout  = "2006-01-02"
+	MonthLayout = "2006-01"
+
+	MIMEApplicationMergePatchJSON = "application/merge-patch+json"
+
+	HeaderETag           = "ETag"
+	HeaderIfMatch        = "If-Match"
+	HeaderAPIKey         = "X-API-Key"
+	HeaderRetryAfter     = "Retry-After"
+	HeaderReferrerPolicy = "Referrer-Policy"
 )
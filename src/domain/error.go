@@ -9,4 +9,10 @@ var (
 	ErrNotFound = errors.New("your requested item is not found")
 	// ErrBadParamInput will throw if the given request-body or params is not valid
 	ErrBadParamInput = errors.New("given param is not valid")
+	// ErrPreconditionFailed will throw if the item was changed since the given version
+	ErrPreconditionFailed = errors.New("your requested item has been modified")
+	// ErrConflict will throw if the item would clash with one that exists
+	ErrConflict = errors.New("your requested item already exists")
+	// ErrMinAboveMax will throw if a reading has its min greater than its max
+	ErrMinAboveMax = errors.New("Min. greater than max.")
 )
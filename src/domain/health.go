@@ -0,0 +1,26 @@
+package domain
+
+import "context"
+
+const (
+	HealthUp       = "up"
+	HealthDown     = "down"
+	HealthDraining = "draining"
+)
+
+type (
+	HealthUsecase interface {
+		Check(ctx context.Context) *HealthReport
+		Drain()
+	}
+)
+
+type HealthReport struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+type ComponentHealth struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
@@ -1,30 +1,84 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type (
 	ScaleUsecase interface {
-		Create(param *Scale) error
-		GetScales() (*ScaleResponse, error)
-		GetScale(date string) ([]Scale, error)
-		Update(param *Scale) error
-		Delete(date string) error
+		Create(ctx context.Context, param *Scale) error
+		GetScales(ctx context.Context) (*ScaleResponse, error)
+		GetScalesInRange(ctx context.Context, from, to string) ([]Scale, error)
+		GetSummary(ctx context.Context, from, to string) (*ScaleSummary, error)
+		GetScale(ctx context.Context, date string) ([]Scale, error)
+		Update(ctx context.Context, param *Scale) error
+		Delete(ctx context.Context, date string, version int) error
+		GetHistory(ctx context.Context, date string) ([]ScaleHistory, error)
+		GetTrash(ctx context.Context) ([]Scale, error)
+		Restore(ctx context.Context, date string) error
+		Purge(ctx context.Context, retention time.Duration) error
+		Batch(ctx context.Context, operations []ScaleOperation) ([]ScaleOperationResult, error)
 	}
 
 	ScaleRepository interface {
-		Create(param *Scale) error
-		GetScales() ([]Scale, error)
-		GetScale(date time.Time) ([]Scale, error)
-		Update(param *Scale) error
-		Delete(date time.Time) error
+		Create(ctx context.Context, param *Scale) error
+		GetScales(ctx context.Context) ([]Scale, error)
+		GetScale(ctx context.Context, date time.Time) ([]Scale, error)
+		Update(ctx context.Context, param *Scale) error
+		Delete(ctx context.Context, date time.Time, version int) error
+		GetTrash(ctx context.Context) ([]Scale, error)
+		Restore(ctx context.Context, date time.Time) error
+		Purge(ctx context.Context, before time.Time) error
+		Transaction(ctx context.Context, fn func(tx ScaleRepository) error) error
+		Health(ctx context.Context) error
+		// Dump returns a consistent snapshot of everything stored and Load
+		// replaces it all, for backups and maintenance.
+		Dump(ctx context.Context) (*ScaleSnapshot, error)
+		Load(ctx context.Context, snapshot *ScaleSnapshot) error
+	}
+
+	ScaleHistoryRepository interface {
+		Create(param *ScaleHistory) error
+		GetHistory(date time.Time) ([]ScaleHistory, error)
+		Dump() ([]ScaleHistory, error)
+		Load(history []ScaleHistory) error
+	}
+
+	BackupUsecase interface {
+		Backup(ctx context.Context) (*ScaleBackup, error)
+		Restore(ctx context.Context, backup *ScaleBackup) error
+	}
+
+	ScalePublisher interface {
+		Publish(event ScaleEvent)
+	}
+
+	ScaleStream interface {
+		Publish(event ScaleEvent)
+		Subscribe(lastEventID uint64) *ScaleSubscription
+		Unsubscribe(subscription *ScaleSubscription)
+		Close()
 	}
 )
 
+const (
+	ScaleCreated  = "scale.created"
+	ScaleUpdated  = "scale.updated"
+	ScaleDeleted  = "scale.deleted"
+	ScaleRestored = "scale.restored"
+)
+
+// Scale.Version is the version of its date when the reading was last written,
+// the readings of a date are at the version of the newest one. Writes given a
+// non-zero version fail with ErrPreconditionFailed unless it is current.
 type Scale struct {
-	Date       time.Time `json:"date"`
-	Min        int       `json:"min"`
-	Max        int       `json:"max"`
-	Difference int       `json:"difference"`
+	Date       time.Time  `json:"date"`
+	Min        int        `json:"min"`
+	Max        int        `json:"max"`
+	Difference int        `json:"difference"`
+	Version    int        `json:"version"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
 }
 
 type ScaleParam struct {
@@ -33,6 +87,54 @@ type ScaleParam struct {
 	Max  int    `json:"max"`
 }
 
+const (
+	ScaleOperationCreate = "create"
+	ScaleOperationUpdate = "update"
+	ScaleOperationDelete = "delete"
+)
+
+// ScaleOperation is one write of a batch, Min and Max are ignored by deletes
+// and Version makes updates and deletes conditional like If-Match.
+type ScaleOperation struct {
+	Op      string `json:"op" validate:"required,oneof=create update delete"`
+	Date    string `json:"date" validate:"required"`
+	Min     int    `json:"min"`
+	Max     int    `json:"max"`
+	Version int    `json:"version"`
+}
+
+type ScaleOperationResult struct {
+	Op     string `json:"op"`
+	Date   string `json:"date"`
+	Status int    `json:"status"`
+	Scale  *Scale `json:"scale,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ScaleBatchParam struct {
+	Operations []ScaleOperation `json:"operations" validate:"required,min=1,max=100,dive"`
+}
+
+// ScaleSnapshot holds every reading as stored, deleted ones included, with
+// their history. Repositories only keep the readings, History is filled by
+// the audit decorator which keeps it.
+type ScaleSnapshot struct {
+	Scales  []Scale        `json:"scales"`
+	History []ScaleHistory `json:"history"`
+}
+
+// ScaleBackup is a snapshot with the SHA-256 of its compact JSON as Checksum.
+// ScaleBackupVersion changes whenever reading an older backup needs care,
+// version 1 only had the readings, at the top level.
+type ScaleBackup struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	Checksum  string         `json:"checksum"`
+	Snapshot  *ScaleSnapshot `json:"snapshot"`
+}
+
+const ScaleBackupVersion = 2
+
 type ScaleAverrage struct {
 	Min        float64 `json:"min"`
 	Max        float64 `json:"max"`
@@ -43,3 +145,50 @@ type ScaleResponse struct {
 	Scales  []Scale        `json:"scales"`
 	Average *ScaleAverrage `json:"average"`
 }
+
+// ScaleSummary leaves everything but Count nil without readings. First and
+// Last are the dates of the oldest and newest reading, Lowest the lowest min
+// and Highest the highest max.
+type ScaleSummary struct {
+	Count   int            `json:"count"`
+	First   *time.Time     `json:"first"`
+	Last    *time.Time     `json:"last"`
+	Lowest  *int           `json:"lowest"`
+	Highest *int           `json:"highest"`
+	Average *ScaleAverrage `json:"average"`
+}
+
+// ScaleEvent is published after a successful write, Scale only holds the
+// date for ScaleDeleted.
+type ScaleEvent struct {
+	Type       string    `json:"type"`
+	Scale      Scale     `json:"scale"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// ScaleHistory records one write to the readings of a date, Action is the
+// ScaleEvent type of the write and Before/After the readings around it.
+type ScaleHistory struct {
+	Date      time.Time `json:"date"`
+	Action    string    `json:"action"`
+	Actor     string    `json:"actor"`
+	Before    []Scale   `json:"before"`
+	After     []Scale   `json:"after"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type ScaleStreamEvent struct {
+	ID uint64
+	ScaleEvent
+}
+
+// ScaleSubscription replays the events after the requested id in Backlog
+// before Events delivers new ones. Events is closed when the subscriber falls
+// too far behind, Missed is set when the requested id already left the log.
+// Closed is set before Events closes when the stream itself closes.
+type ScaleSubscription struct {
+	Backlog []ScaleStreamEvent
+	Events  <-chan ScaleStreamEvent
+	Missed  bool
+	Closed  bool
+}
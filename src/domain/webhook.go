@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	WebhookUsecase interface {
+		Publish(event ScaleEvent)
+		Create(param *WebhookParam) (*Webhook, error)
+		GetWebhooks() ([]Webhook, error)
+		Delete(id string) error
+		GetDeliveries(id string) ([]WebhookDelivery, error)
+		Close(ctx context.Context) error
+	}
+
+	WebhookRepository interface {
+		Create(param *Webhook) error
+		GetWebhooks() ([]Webhook, error)
+		GetWebhook(id string) (*Webhook, error)
+		Delete(id string) error
+		CreateDelivery(param *WebhookDelivery) error
+		GetDeliveries(webhookID string) ([]WebhookDelivery, error)
+	}
+)
+
+type Webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookParam struct {
+	URL    string   `json:"url" validate:"required,url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events" validate:"dive,oneof=scale.created scale.updated scale.deleted scale.restored"`
+}
+
+type WebhookDelivery struct {
+	ID         string    `json:"id"`
+	WebhookID  string    `json:"webhook_id"`
+	Event      string    `json:"event"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `json:"error"`
+	Success    bool      `json:"success"`
+	CreatedAt  time.Time `json:"created_at"`
+}
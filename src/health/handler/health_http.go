@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+)
+
+type healthHandler struct {
+	healthUsecase domain.HealthUsecase
+}
+
+func NewHealthHandler(e *echo.Echo, healthUsecase domain.HealthUsecase) {
+	handler := &healthHandler{
+		healthUsecase: healthUsecase,
+	}
+
+	e.GET("/healthz", handler.Liveness)
+	e.GET("/readyz", handler.Readiness)
+}
+
+// Liveness only tells the process still answers, restarting it would not fix
+// a broken backend, so components are left to Readiness.
+func (h *healthHandler) Liveness(c echo.Context) error {
+	return c.JSON(http.StatusOK, helper.Response(200, "Alive", nil, nil))
+}
+
+func (h *healthHandler) Readiness(c echo.Context) error {
+	report := h.healthUsecase.Check(c.Request().Context())
+	if report.Status != domain.HealthUp {
+		code := http.StatusServiceUnavailable
+		return c.JSON(code, helper.Response(code, "Not ready", report, "service is "+report.Status))
+	}
+
+	data := helper.Response(200, "Ready", report, nil)
+	return c.JSON(http.StatusOK, data)
+}
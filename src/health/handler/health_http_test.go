@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	helper.InitTime()
+}
+
+func TestLiveness(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := healthHandler{}
+
+	if assert.NoError(t, h.Liveness(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `{"code":200,"message":"Alive","data":null,"errors":null}
+`, rec.Body.String())
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	healthMock := mock_domain.NewMockHealthUsecase(ctrl)
+
+	tests := []struct {
+		name       string
+		wantCode   int
+		wantResult string
+		mock       func()
+	}{
+		{
+			name:     "ready",
+			wantCode: http.StatusOK,
+			wantResult: `{"code":200,"message":"Ready","data":{"status":"up","components":{"scale_repository":{"status":"up"}}},"errors":null}
+`,
+			mock: func() {
+				healthMock.EXPECT().Check(gomock.Any()).Return(&domain.HealthReport{
+					Status: domain.HealthUp,
+					Components: map[string]domain.ComponentHealth{
+						"scale_repository": {Status: domain.HealthUp},
+					},
+				})
+			},
+		},
+		{
+			name:     "down",
+			wantCode: http.StatusServiceUnavailable,
+			wantResult: `{"code":503,"message":"Not ready","data":{"status":"down","components":{"scale_repository":{"status":"down","error":"context deadline exceeded"}}},"errors":"service is down"}
+`,
+			mock: func() {
+				healthMock.EXPECT().Check(gomock.Any()).Return(&domain.HealthReport{
+					Status: domain.HealthDown,
+					Components: map[string]domain.ComponentHealth{
+						"scale_repository": {Status: domain.HealthDown, Error: "context deadline exceeded"},
+					},
+				})
+			},
+		},
+		{
+			name:     "draining",
+			wantCode: http.StatusServiceUnavailable,
+			wantResult: `{"code":503,"message":"Not ready","data":{"status":"draining","components":{"scale_repository":{"status":"up"}}},"errors":"service is draining"}
+`,
+			mock: func() {
+				healthMock.EXPECT().Check(gomock.Any()).Return(&domain.HealthReport{
+					Status: domain.HealthDraining,
+					Components: map[string]domain.ComponentHealth{
+						"scale_repository": {Status: domain.HealthUp},
+					},
+				})
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			h := healthHandler{
+				healthUsecase: healthMock,
+			}
+
+			if assert.NoError(t, h.Readiness(c)) {
+				assert.Equal(t, test.wantCode, rec.Code)
+				assert.Equal(t, test.wantResult, rec.Body.String())
+			}
+		})
+	}
+}
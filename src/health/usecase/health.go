@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/scale/src/domain"
+)
+
+const defaultTimeout = 2 * time.Second
+
+type healthUsecase struct {
+	scaleRepository domain.ScaleRepository
+	timeout         time.Duration
+	draining        int32
+}
+
+func NewHealthUsecase(scaleRepository domain.ScaleRepository) domain.HealthUsecase {
+	return &healthUsecase{
+		scaleRepository: scaleRepository,
+		timeout:         defaultTimeout,
+	}
+}
+
+// Check asks every component for its health. The report is down when any of
+// them is, and draining from the moment Drain is called whatever they say.
+func (h *healthUsecase) Check(ctx context.Context) *domain.HealthReport {
+	report := &domain.HealthReport{
+		Status: domain.HealthUp,
+		Components: map[string]domain.ComponentHealth{
+			"scale_repository": h.check(ctx, h.scaleRepository.Health),
+		},
+	}
+	for _, component := range report.Components {
+		if component.Status != domain.HealthUp {
+			report.Status = domain.HealthDown
+		}
+	}
+	if atomic.LoadInt32(&h.draining) == 1 {
+		report.Status = domain.HealthDraining
+	}
+	return report
+}
+
+// check gives up after the timeout even if health does not, so a backend stuck
+// behind a long write reports down instead of hanging the probe.
+func (h *healthUsecase) check(ctx context.Context, health func(ctx context.Context) error) domain.ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- health(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil {
+		return domain.ComponentHealth{Status: domain.HealthDown, Error: err.Error()}
+	}
+	return domain.ComponentHealth{Status: domain.HealthUp}
+}
+
+func (h *healthUsecase) Drain() {
+	atomic.StoreInt32(&h.draining, 1)
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	helper.InitTime()
+}
+
+func TestCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaleMock := mock_domain.NewMockScaleRepository(ctrl)
+
+	tests := []struct {
+		name       string
+		drain      bool
+		wantResult *domain.HealthReport
+		mock       func()
+	}{
+		{
+			name: "up",
+			wantResult: &domain.HealthReport{
+				Status: domain.HealthUp,
+				Components: map[string]domain.ComponentHealth{
+					"scale_repository": {Status: domain.HealthUp},
+				},
+			},
+			mock: func() {
+				scaleMock.EXPECT().Health(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "down",
+			wantResult: &domain.HealthReport{
+				Status: domain.HealthDown,
+				Components: map[string]domain.ComponentHealth{
+					"scale_repository": {Status: domain.HealthDown, Error: "some error"},
+				},
+			},
+			mock: func() {
+				scaleMock.EXPECT().Health(gomock.Any()).Return(errors.New("some error"))
+			},
+		},
+		{
+			name: "timeout",
+			wantResult: &domain.HealthReport{
+				Status: domain.HealthDown,
+				Components: map[string]domain.ComponentHealth{
+					"scale_repository": {Status: domain.HealthDown, Error: "context deadline exceeded"},
+				},
+			},
+			mock: func() {
+				scaleMock.EXPECT().Health(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+					time.Sleep(50 * time.Millisecond)
+					return nil
+				})
+			},
+		},
+		{
+			name:  "draining",
+			drain: true,
+			wantResult: &domain.HealthReport{
+				Status: domain.HealthDraining,
+				Components: map[string]domain.ComponentHealth{
+					"scale_repository": {Status: domain.HealthUp},
+				},
+			},
+			mock: func() {
+				scaleMock.EXPECT().Health(gomock.Any()).Return(nil)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+			uc := &healthUsecase{
+				scaleRepository: scaleMock,
+				timeout:         10 * time.Millisecond,
+			}
+			if test.drain {
+				uc.Drain()
+			}
+			got := uc.Check(context.Background())
+			assert.Equal(t, test.wantResult, got)
+		})
+	}
+}
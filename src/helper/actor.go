@@ -0,0 +1,16 @@
+package helper
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx telling storage who is making the change.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// GetActor returns the actor set by WithActor, empty when there is none.
+func GetActor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
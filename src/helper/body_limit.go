@@ -0,0 +1,43 @@
+package helper
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/labstack/echo"
+)
+
+// BodyLimit answers 413 to requests with a body over limit bytes, before any
+// handler reads it. Routes reading a body are given it when registered.
+func BodyLimit(limit int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.ContentLength > limit {
+				return bodyTooLarge(c, limit)
+			}
+			// the length is unknown for chunked bodies, read one byte past
+			// the limit to tell
+			if req.ContentLength < 0 {
+				body, err := ioutil.ReadAll(io.LimitReader(req.Body, limit+1))
+				if err != nil {
+					code := http.StatusBadRequest
+					return c.JSON(code, Response(code, "Failed read body", nil, err.Error()))
+				}
+				if int64(len(body)) > limit {
+					return bodyTooLarge(c, limit)
+				}
+				req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			}
+			return next(c)
+		}
+	}
+}
+
+func bodyTooLarge(c echo.Context, limit int64) error {
+	code := http.StatusRequestEntityTooLarge
+	return c.JSON(code, Response(code, "Request body too large", nil, fmt.Sprintf("body exceeds %d bytes", limit)))
+}
@@ -0,0 +1,74 @@
+package helper
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimit(t *testing.T) {
+	type args struct {
+		body    string
+		chunked bool
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantCode   int
+		wantResult string
+	}{
+		{
+			name:       "within limit",
+			args:       args{body: `{"min":45}`},
+			wantCode:   http.StatusOK,
+			wantResult: `{"min":45}`,
+		},
+		{
+			name:       "chunked within limit",
+			args:       args{body: `{"min":45}`, chunked: true},
+			wantCode:   http.StatusOK,
+			wantResult: `{"min":45}`,
+		},
+		{
+			name:     "too large",
+			args:     args{body: `{"min":45,"max":50}`},
+			wantCode: http.StatusRequestEntityTooLarge,
+			wantResult: `{"code":413,"message":"Request body too large","data":null,"errors":"body exceeds 16 bytes"}
+`,
+		},
+		{
+			name:     "chunked too large",
+			args:     args{body: `{"min":45,"max":50}`, chunked: true},
+			wantCode: http.StatusRequestEntityTooLarge,
+			wantResult: `{"code":413,"message":"Request body too large","data":null,"errors":"body exceeds 16 bytes"}
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/scale", strings.NewReader(test.args.body))
+			if test.args.chunked {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := BodyLimit(16)(func(c echo.Context) error {
+				body, err := ioutil.ReadAll(c.Request().Body)
+				if err != nil {
+					return err
+				}
+				return c.String(http.StatusOK, string(body))
+			})(c)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantCode, rec.Code)
+			assert.Equal(t, test.wantResult, rec.Body.String())
+		})
+	}
+}
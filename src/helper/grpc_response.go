@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"context"
+
+	"github.com/scale/src/domain"
+	"google.golang.org/grpc/codes"
+)
+
+func GetGRPCCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+
+	switch err.Error() {
+	case domain.ErrNotFound.Error():
+		return codes.NotFound
+	case domain.ErrPreconditionFailed.Error():
+		return codes.Aborted
+	case domain.ErrConflict.Error():
+		return codes.AlreadyExists
+	case domain.ErrBadParamInput.Error(), domain.ErrMinAboveMax.Error():
+		return codes.InvalidArgument
+	case context.DeadlineExceeded.Error():
+		return codes.DeadlineExceeded
+	case context.Canceled.Error():
+		return codes.Canceled
+	default:
+		return codes.Internal
+	}
+}
@@ -1,6 +1,7 @@
 package helper
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/scale/src/domain"
@@ -31,8 +32,14 @@ func GetStatusCode(err error) int {
 	switch err.Error() {
 	case domain.ErrNotFound.Error():
 		return http.StatusNotFound
-	case domain.ErrBadParamInput.Error():
+	case domain.ErrPreconditionFailed.Error():
+		return http.StatusPreconditionFailed
+	case domain.ErrConflict.Error():
+		return http.StatusConflict
+	case domain.ErrBadParamInput.Error(), domain.ErrMinAboveMax.Error():
 		return http.StatusBadRequest
+	case context.DeadlineExceeded.Error():
+		return http.StatusGatewayTimeout
 	default:
 		return http.StatusInternalServerError
 	}
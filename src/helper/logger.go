@@ -0,0 +1,54 @@
+package helper
+
+import (
+	"context"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+type loggerKey struct{}
+
+// NewLogger sets the standard logger up to write JSON to stdout at level, such
+// as debug or warn, info when empty, and returns it. Loggers are passed where
+// they can be, what runs outside of a request falls back to this one.
+func NewLogger(level string) (*logrus.Logger, error) {
+	if level == "" {
+		level = logrus.InfoLevel.String()
+	}
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := logrus.StandardLogger()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.SetOutput(os.Stdout)
+	logger.SetLevel(lvl)
+	return logger, nil
+}
+
+// WithLogger returns a copy of ctx carrying logger, usually one tagged with the
+// request ID.
+func WithLogger(ctx context.Context, logger logrus.FieldLogger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// GetLogger returns the logger set by WithLogger, the standard logger when
+// there is none.
+func GetLogger(ctx context.Context) logrus.FieldLogger {
+	return GetLoggerOr(ctx, nil)
+}
+
+// GetLoggerOr is GetLogger falling back to fallback, then to the standard
+// logger when it is nil.
+func GetLoggerOr(ctx context.Context, fallback logrus.FieldLogger) logrus.FieldLogger {
+	logger, ok := ctx.Value(loggerKey{}).(logrus.FieldLogger)
+	if ok {
+		return logger
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return logrus.StandardLogger()
+}
@@ -1,6 +1,7 @@
 package helper
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -26,3 +27,16 @@ func InitTime() (err error) {
 func GetLocation() *time.Location {
 	return loc
 }
+
+func MonthName(t time.Time) string {
+	return months[t.Month()-1]
+}
+
+func WeekdayName(t time.Time) string {
+	return weekdays[t.Weekday()]
+}
+
+// FormatDate formats the date with the Indonesian names, e.g. "Rabu, 22 Agustus 2018"
+func FormatDate(t time.Time) string {
+	return fmt.Sprintf("%s, %d %s %d", WeekdayName(t), t.Day(), MonthName(t), t.Year())
+}
@@ -5,6 +5,7 @@
 package mock_domain
 
 import (
+	context "context"
 	reflect "reflect"
 	time "time"
 
@@ -35,76 +36,179 @@ func (m *MockScaleUsecase) EXPECT() *MockScaleUsecaseMockRecorder {
 	return m.recorder
 }
 
+// Batch mocks base method.
+func (m *MockScaleUsecase) Batch(ctx context.Context, operations []domain.ScaleOperation) ([]domain.ScaleOperationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Batch", ctx, operations)
+	ret0, _ := ret[0].([]domain.ScaleOperationResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Batch indicates an expected call of Batch.
+func (mr *MockScaleUsecaseMockRecorder) Batch(ctx, operations interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Batch", reflect.TypeOf((*MockScaleUsecase)(nil).Batch), ctx, operations)
+}
+
 // Create mocks base method.
-func (m *MockScaleUsecase) Create(param *domain.Scale) error {
+func (m *MockScaleUsecase) Create(ctx context.Context, param *domain.Scale) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", param)
+	ret := m.ctrl.Call(m, "Create", ctx, param)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockScaleUsecaseMockRecorder) Create(param interface{}) *gomock.Call {
+func (mr *MockScaleUsecaseMockRecorder) Create(ctx, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScaleUsecase)(nil).Create), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScaleUsecase)(nil).Create), ctx, param)
 }
 
 // Delete mocks base method.
-func (m *MockScaleUsecase) Delete(date string) error {
+func (m *MockScaleUsecase) Delete(ctx context.Context, date string, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", date)
+	ret := m.ctrl.Call(m, "Delete", ctx, date, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockScaleUsecaseMockRecorder) Delete(date interface{}) *gomock.Call {
+func (mr *MockScaleUsecaseMockRecorder) Delete(ctx, date, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScaleUsecase)(nil).Delete), date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScaleUsecase)(nil).Delete), ctx, date, version)
+}
+
+// GetHistory mocks base method.
+func (m *MockScaleUsecase) GetHistory(ctx context.Context, date string) ([]domain.ScaleHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, date)
+	ret0, _ := ret[0].([]domain.ScaleHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockScaleUsecaseMockRecorder) GetHistory(ctx, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockScaleUsecase)(nil).GetHistory), ctx, date)
 }
 
 // GetScale mocks base method.
-func (m *MockScaleUsecase) GetScale(date string) ([]domain.Scale, error) {
+func (m *MockScaleUsecase) GetScale(ctx context.Context, date string) ([]domain.Scale, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetScale", date)
+	ret := m.ctrl.Call(m, "GetScale", ctx, date)
 	ret0, _ := ret[0].([]domain.Scale)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetScale indicates an expected call of GetScale.
-func (mr *MockScaleUsecaseMockRecorder) GetScale(date interface{}) *gomock.Call {
+func (mr *MockScaleUsecaseMockRecorder) GetScale(ctx, date interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScale", reflect.TypeOf((*MockScaleUsecase)(nil).GetScale), date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScale", reflect.TypeOf((*MockScaleUsecase)(nil).GetScale), ctx, date)
 }
 
 // GetScales mocks base method.
-func (m *MockScaleUsecase) GetScales() (*domain.ScaleResponse, error) {
+func (m *MockScaleUsecase) GetScales(ctx context.Context) (*domain.ScaleResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetScales")
+	ret := m.ctrl.Call(m, "GetScales", ctx)
 	ret0, _ := ret[0].(*domain.ScaleResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetScales indicates an expected call of GetScales.
-func (mr *MockScaleUsecaseMockRecorder) GetScales() *gomock.Call {
+func (mr *MockScaleUsecaseMockRecorder) GetScales(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScales", reflect.TypeOf((*MockScaleUsecase)(nil).GetScales), ctx)
+}
+
+// GetScalesInRange mocks base method.
+func (m *MockScaleUsecase) GetScalesInRange(ctx context.Context, from, to string) ([]domain.Scale, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScalesInRange", ctx, from, to)
+	ret0, _ := ret[0].([]domain.Scale)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScalesInRange indicates an expected call of GetScalesInRange.
+func (mr *MockScaleUsecaseMockRecorder) GetScalesInRange(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScalesInRange", reflect.TypeOf((*MockScaleUsecase)(nil).GetScalesInRange), ctx, from, to)
+}
+
+// GetSummary mocks base method.
+func (m *MockScaleUsecase) GetSummary(ctx context.Context, from, to string) (*domain.ScaleSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSummary", ctx, from, to)
+	ret0, _ := ret[0].(*domain.ScaleSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSummary indicates an expected call of GetSummary.
+func (mr *MockScaleUsecaseMockRecorder) GetSummary(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSummary", reflect.TypeOf((*MockScaleUsecase)(nil).GetSummary), ctx, from, to)
+}
+
+// GetTrash mocks base method.
+func (m *MockScaleUsecase) GetTrash(ctx context.Context) ([]domain.Scale, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx)
+	ret0, _ := ret[0].([]domain.Scale)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockScaleUsecaseMockRecorder) GetTrash(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScales", reflect.TypeOf((*MockScaleUsecase)(nil).GetScales))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockScaleUsecase)(nil).GetTrash), ctx)
+}
+
+// Purge mocks base method.
+func (m *MockScaleUsecase) Purge(ctx context.Context, retention time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, retention)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockScaleUsecaseMockRecorder) Purge(ctx, retention interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockScaleUsecase)(nil).Purge), ctx, retention)
+}
+
+// Restore mocks base method.
+func (m *MockScaleUsecase) Restore(ctx context.Context, date string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockScaleUsecaseMockRecorder) Restore(ctx, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockScaleUsecase)(nil).Restore), ctx, date)
 }
 
 // Update mocks base method.
-func (m *MockScaleUsecase) Update(param *domain.Scale) error {
+func (m *MockScaleUsecase) Update(ctx context.Context, param *domain.Scale) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", param)
+	ret := m.ctrl.Call(m, "Update", ctx, param)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockScaleUsecaseMockRecorder) Update(param interface{}) *gomock.Call {
+func (mr *MockScaleUsecaseMockRecorder) Update(ctx, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScaleUsecase)(nil).Update), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScaleUsecase)(nil).Update), ctx, param)
 }
 
 // MockScaleRepository is a mock of ScaleRepository interface.
@@ -131,73 +235,414 @@ func (m *MockScaleRepository) EXPECT() *MockScaleRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockScaleRepository) Create(param *domain.Scale) error {
+func (m *MockScaleRepository) Create(ctx context.Context, param *domain.Scale) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", param)
+	ret := m.ctrl.Call(m, "Create", ctx, param)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockScaleRepositoryMockRecorder) Create(param interface{}) *gomock.Call {
+func (mr *MockScaleRepositoryMockRecorder) Create(ctx, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScaleRepository)(nil).Create), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScaleRepository)(nil).Create), ctx, param)
 }
 
 // Delete mocks base method.
-func (m *MockScaleRepository) Delete(date time.Time) error {
+func (m *MockScaleRepository) Delete(ctx context.Context, date time.Time, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", date)
+	ret := m.ctrl.Call(m, "Delete", ctx, date, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockScaleRepositoryMockRecorder) Delete(date interface{}) *gomock.Call {
+func (mr *MockScaleRepositoryMockRecorder) Delete(ctx, date, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScaleRepository)(nil).Delete), ctx, date, version)
+}
+
+// Dump mocks base method.
+func (m *MockScaleRepository) Dump(ctx context.Context) (*domain.ScaleSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dump", ctx)
+	ret0, _ := ret[0].(*domain.ScaleSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dump indicates an expected call of Dump.
+func (mr *MockScaleRepositoryMockRecorder) Dump(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScaleRepository)(nil).Delete), date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dump", reflect.TypeOf((*MockScaleRepository)(nil).Dump), ctx)
 }
 
 // GetScale mocks base method.
-func (m *MockScaleRepository) GetScale(date time.Time) ([]domain.Scale, error) {
+func (m *MockScaleRepository) GetScale(ctx context.Context, date time.Time) ([]domain.Scale, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetScale", date)
+	ret := m.ctrl.Call(m, "GetScale", ctx, date)
 	ret0, _ := ret[0].([]domain.Scale)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetScale indicates an expected call of GetScale.
-func (mr *MockScaleRepositoryMockRecorder) GetScale(date interface{}) *gomock.Call {
+func (mr *MockScaleRepositoryMockRecorder) GetScale(ctx, date interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScale", reflect.TypeOf((*MockScaleRepository)(nil).GetScale), date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScale", reflect.TypeOf((*MockScaleRepository)(nil).GetScale), ctx, date)
 }
 
 // GetScales mocks base method.
-func (m *MockScaleRepository) GetScales() ([]domain.Scale, error) {
+func (m *MockScaleRepository) GetScales(ctx context.Context) ([]domain.Scale, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetScales")
+	ret := m.ctrl.Call(m, "GetScales", ctx)
 	ret0, _ := ret[0].([]domain.Scale)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetScales indicates an expected call of GetScales.
-func (mr *MockScaleRepositoryMockRecorder) GetScales() *gomock.Call {
+func (mr *MockScaleRepositoryMockRecorder) GetScales(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScales", reflect.TypeOf((*MockScaleRepository)(nil).GetScales), ctx)
+}
+
+// GetTrash mocks base method.
+func (m *MockScaleRepository) GetTrash(ctx context.Context) ([]domain.Scale, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx)
+	ret0, _ := ret[0].([]domain.Scale)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockScaleRepositoryMockRecorder) GetTrash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockScaleRepository)(nil).GetTrash), ctx)
+}
+
+// Health mocks base method.
+func (m *MockScaleRepository) Health(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Health", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Health indicates an expected call of Health.
+func (mr *MockScaleRepositoryMockRecorder) Health(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockScaleRepository)(nil).Health), ctx)
+}
+
+// Load mocks base method.
+func (m *MockScaleRepository) Load(ctx context.Context, snapshot *domain.ScaleSnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", ctx, snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockScaleRepositoryMockRecorder) Load(ctx, snapshot interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScales", reflect.TypeOf((*MockScaleRepository)(nil).GetScales))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockScaleRepository)(nil).Load), ctx, snapshot)
+}
+
+// Purge mocks base method.
+func (m *MockScaleRepository) Purge(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockScaleRepositoryMockRecorder) Purge(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockScaleRepository)(nil).Purge), ctx, before)
+}
+
+// Restore mocks base method.
+func (m *MockScaleRepository) Restore(ctx context.Context, date time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockScaleRepositoryMockRecorder) Restore(ctx, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockScaleRepository)(nil).Restore), ctx, date)
+}
+
+// Transaction mocks base method.
+func (m *MockScaleRepository) Transaction(ctx context.Context, fn func(domain.ScaleRepository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockScaleRepositoryMockRecorder) Transaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockScaleRepository)(nil).Transaction), ctx, fn)
 }
 
 // Update mocks base method.
-func (m *MockScaleRepository) Update(param *domain.Scale) error {
+func (m *MockScaleRepository) Update(ctx context.Context, param *domain.Scale) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", param)
+	ret := m.ctrl.Call(m, "Update", ctx, param)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockScaleRepositoryMockRecorder) Update(param interface{}) *gomock.Call {
+func (mr *MockScaleRepositoryMockRecorder) Update(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScaleRepository)(nil).Update), ctx, param)
+}
+
+// MockScaleHistoryRepository is a mock of ScaleHistoryRepository interface.
+type MockScaleHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockScaleHistoryRepositoryMockRecorder
+}
+
+// MockScaleHistoryRepositoryMockRecorder is the mock recorder for MockScaleHistoryRepository.
+type MockScaleHistoryRepositoryMockRecorder struct {
+	mock *MockScaleHistoryRepository
+}
+
+// NewMockScaleHistoryRepository creates a new mock instance.
+func NewMockScaleHistoryRepository(ctrl *gomock.Controller) *MockScaleHistoryRepository {
+	mock := &MockScaleHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockScaleHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScaleHistoryRepository) EXPECT() *MockScaleHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockScaleHistoryRepository) Create(param *domain.ScaleHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", param)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockScaleHistoryRepositoryMockRecorder) Create(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScaleHistoryRepository)(nil).Create), param)
+}
+
+// Dump mocks base method.
+func (m *MockScaleHistoryRepository) Dump() ([]domain.ScaleHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dump")
+	ret0, _ := ret[0].([]domain.ScaleHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dump indicates an expected call of Dump.
+func (mr *MockScaleHistoryRepositoryMockRecorder) Dump() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dump", reflect.TypeOf((*MockScaleHistoryRepository)(nil).Dump))
+}
+
+// GetHistory mocks base method.
+func (m *MockScaleHistoryRepository) GetHistory(date time.Time) ([]domain.ScaleHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", date)
+	ret0, _ := ret[0].([]domain.ScaleHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockScaleHistoryRepositoryMockRecorder) GetHistory(date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockScaleHistoryRepository)(nil).GetHistory), date)
+}
+
+// Load mocks base method.
+func (m *MockScaleHistoryRepository) Load(history []domain.ScaleHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", history)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockScaleHistoryRepositoryMockRecorder) Load(history interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockScaleHistoryRepository)(nil).Load), history)
+}
+
+// MockBackupUsecase is a mock of BackupUsecase interface.
+type MockBackupUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackupUsecaseMockRecorder
+}
+
+// MockBackupUsecaseMockRecorder is the mock recorder for MockBackupUsecase.
+type MockBackupUsecaseMockRecorder struct {
+	mock *MockBackupUsecase
+}
+
+// NewMockBackupUsecase creates a new mock instance.
+func NewMockBackupUsecase(ctrl *gomock.Controller) *MockBackupUsecase {
+	mock := &MockBackupUsecase{ctrl: ctrl}
+	mock.recorder = &MockBackupUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackupUsecase) EXPECT() *MockBackupUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Backup mocks base method.
+func (m *MockBackupUsecase) Backup(ctx context.Context) (*domain.ScaleBackup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backup", ctx)
+	ret0, _ := ret[0].(*domain.ScaleBackup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Backup indicates an expected call of Backup.
+func (mr *MockBackupUsecaseMockRecorder) Backup(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backup", reflect.TypeOf((*MockBackupUsecase)(nil).Backup), ctx)
+}
+
+// Restore mocks base method.
+func (m *MockBackupUsecase) Restore(ctx context.Context, backup *domain.ScaleBackup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, backup)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockBackupUsecaseMockRecorder) Restore(ctx, backup interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockBackupUsecase)(nil).Restore), ctx, backup)
+}
+
+// MockScalePublisher is a mock of ScalePublisher interface.
+type MockScalePublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockScalePublisherMockRecorder
+}
+
+// MockScalePublisherMockRecorder is the mock recorder for MockScalePublisher.
+type MockScalePublisherMockRecorder struct {
+	mock *MockScalePublisher
+}
+
+// NewMockScalePublisher creates a new mock instance.
+func NewMockScalePublisher(ctrl *gomock.Controller) *MockScalePublisher {
+	mock := &MockScalePublisher{ctrl: ctrl}
+	mock.recorder = &MockScalePublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScalePublisher) EXPECT() *MockScalePublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockScalePublisher) Publish(event domain.ScaleEvent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", event)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockScalePublisherMockRecorder) Publish(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockScalePublisher)(nil).Publish), event)
+}
+
+// MockScaleStream is a mock of ScaleStream interface.
+type MockScaleStream struct {
+	ctrl     *gomock.Controller
+	recorder *MockScaleStreamMockRecorder
+}
+
+// MockScaleStreamMockRecorder is the mock recorder for MockScaleStream.
+type MockScaleStreamMockRecorder struct {
+	mock *MockScaleStream
+}
+
+// NewMockScaleStream creates a new mock instance.
+func NewMockScaleStream(ctrl *gomock.Controller) *MockScaleStream {
+	mock := &MockScaleStream{ctrl: ctrl}
+	mock.recorder = &MockScaleStreamMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScaleStream) EXPECT() *MockScaleStreamMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockScaleStream) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockScaleStreamMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockScaleStream)(nil).Close))
+}
+
+// Publish mocks base method.
+func (m *MockScaleStream) Publish(event domain.ScaleEvent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", event)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockScaleStreamMockRecorder) Publish(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockScaleStream)(nil).Publish), event)
+}
+
+// Subscribe mocks base method.
+func (m *MockScaleStream) Subscribe(lastEventID uint64) *domain.ScaleSubscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", lastEventID)
+	ret0, _ := ret[0].(*domain.ScaleSubscription)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockScaleStreamMockRecorder) Subscribe(lastEventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockScaleStream)(nil).Subscribe), lastEventID)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockScaleStream) Unsubscribe(subscription *domain.ScaleSubscription) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Unsubscribe", subscription)
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockScaleStreamMockRecorder) Unsubscribe(subscription interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScaleRepository)(nil).Update), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockScaleStream)(nil).Unsubscribe), subscription)
 }
@@ -0,0 +1,741 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	backuphandler "github.com/scale/src/backup/handler"
+	"github.com/scale/src/domain"
+	healthhandler "github.com/scale/src/health/handler"
+	"github.com/scale/src/helper"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/scale/src/scale/handler"
+	"github.com/scale/src/scale/maintenance"
+	"github.com/scale/src/scale/stream"
+	"github.com/scale/src/ui"
+	webhookhandler "github.com/scale/src/webhook/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	helper.InitTime()
+}
+
+const adminToken = "secret"
+
+func newServer(scaleUsecase domain.ScaleUsecase, webhookUsecase domain.WebhookUsecase, healthUsecase domain.HealthUsecase, backupUsecase domain.BackupUsecase) *echo.Echo {
+	e := echo.New()
+	healthhandler.NewHealthHandler(e, healthUsecase)
+	backuphandler.NewBackupHandler(e, backupUsecase, adminToken)
+	handler.NewScaleHandler(e, scaleUsecase)
+	handler.NewScaleGraphQLHandler(e, scaleUsecase)
+	handler.NewScaleStreamHandler(e, stream.NewScaleStream(stream.DefaultLogSize))
+	webhookhandler.NewWebhookHandler(e, webhookUsecase)
+	ui.NewUIHandler(e)
+	NewOpenAPIHandler(e)
+	return e
+}
+
+// specPath converts an echo route path to its OpenAPI path template.
+func specPath(path string) string {
+	path = regexp.MustCompile(`:(\w+)`).ReplaceAllString(path, "{$1}")
+	return strings.Replace(path, "*", "{path}", 1)
+}
+
+func TestNewOpenAPIHandler(t *testing.T) {
+	e := newServer(nil, nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, echo.MIMEApplicationJSONCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.True(t, json.Valid(rec.Body.Bytes()))
+}
+
+func TestRoutesDocumented(t *testing.T) {
+	e := newServer(nil, nil, nil, nil)
+
+	spec, err := decode(document)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, route := range e.Routes() {
+		// echo registers not found catch-alls for every group
+		if strings.HasPrefix(route.Name, "github.com/labstack/echo.(*Group)") {
+			continue
+		}
+		_, ok := operation(spec, route.Method, specPath(route.Path))
+		assert.True(t, ok, "%s %s is not documented", route.Method, route.Path)
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+	webhookMock := mock_domain.NewMockWebhookUsecase(ctrl)
+	healthMock := mock_domain.NewMockHealthUsecase(ctrl)
+	backupMock := mock_domain.NewMockBackupUsecase(ctrl)
+	e := newServer(scaleMock, webhookMock, healthMock, backupMock)
+
+	type args struct {
+		method string
+		target string
+		path   string
+		body   string
+		token  string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantCode int
+		mock     func()
+	}{
+		{
+			name: "get scales",
+			args: args{
+				method: http.MethodGet,
+				target: "/scales",
+				path:   "/scales",
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(&domain.ScaleResponse{
+					Scales: []domain.Scale{
+						{
+							Date:       date,
+							Min:        47,
+							Max:        50,
+							Difference: 3,
+						},
+					},
+					Average: &domain.ScaleAverrage{
+						Min:        47,
+						Max:        50,
+						Difference: 3,
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "get scales error",
+			args: args{
+				method: http.MethodGet,
+				target: "/scales",
+				path:   "/scales",
+			},
+			wantCode: http.StatusInternalServerError,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+		},
+		{
+			name: "get scale",
+			args: args{
+				method: http.MethodGet,
+				target: "/scale?date=2022-02-01",
+				path:   "/scale",
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{
+					{
+						Date:       date,
+						Min:        47,
+						Max:        50,
+						Difference: 3,
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "get scale error param",
+			args: args{
+				method: http.MethodGet,
+				target: "/scale?date=date",
+				path:   "/scale",
+			},
+			wantCode: http.StatusBadRequest,
+			mock:     func() {},
+		},
+		{
+			name: "create",
+			args: args{
+				method: http.MethodPost,
+				target: "/scale",
+				path:   "/scale",
+				body:   `{"date":"2022-02-01","min":45,"max":50}`,
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				scaleMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "create error param",
+			args: args{
+				method: http.MethodPost,
+				target: "/scale",
+				path:   "/scale",
+				body:   `{"date":"date","min":45,"max":50}`,
+			},
+			wantCode: http.StatusBadRequest,
+			mock:     func() {},
+		},
+		{
+			name: "create body too large",
+			args: args{
+				method: http.MethodPost,
+				target: "/scale",
+				path:   "/scale",
+				body:   `{"date":"2022-02-01","min":45,"max":50,"note":"` + strings.Repeat("a", 4<<10) + `"}`,
+			},
+			wantCode: http.StatusRequestEntityTooLarge,
+			mock:     func() {},
+		},
+		{
+			name: "update",
+			args: args{
+				method: http.MethodPatch,
+				target: "/scale",
+				path:   "/scale",
+				body:   `{"date":"2022-02-01","min":45,"max":50}`,
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				scaleMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "delete",
+			args: args{
+				method: http.MethodDelete,
+				target: "/scale?date=2022-02-01",
+				path:   "/scale",
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", 0).Return(nil)
+			},
+		},
+		{
+			name: "delete error",
+			args: args{
+				method: http.MethodDelete,
+				target: "/scale?date=2022-02-01",
+				path:   "/scale",
+			},
+			wantCode: http.StatusNotFound,
+			mock: func() {
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", 0).Return(domain.ErrNotFound)
+			},
+		},
+		{
+			name: "get scales v1",
+			args: args{
+				method: http.MethodGet,
+				target: "/v1/scales",
+				path:   "/v1/scales",
+			},
+			wantCode: http.StatusInternalServerError,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+		},
+		{
+			name: "create v2",
+			args: args{
+				method: http.MethodPost,
+				target: "/v2/scales",
+				path:   "/v2/scales",
+				body:   `{"date":"2022-02-01","min":45,"max":50}`,
+			},
+			wantCode: http.StatusCreated,
+			mock: func() {
+				scaleMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "patch v2",
+			args: args{
+				method: http.MethodPatch,
+				target: "/v2/scales/2022-02-01",
+				path:   "/v2/scales/{date}",
+				body:   `{"max":52}`,
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{
+					{
+						Date:       date,
+						Min:        47,
+						Max:        50,
+						Difference: 3,
+					},
+				}, nil)
+				scaleMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "get v2 not found",
+			args: args{
+				method: http.MethodGet,
+				target: "/v2/scales/2022-02-01",
+				path:   "/v2/scales/{date}",
+			},
+			wantCode: http.StatusNotFound,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{}, nil)
+			},
+		},
+		{
+			name: "graphql",
+			args: args{
+				method: http.MethodPost,
+				target: "/graphql",
+				path:   "/graphql",
+				body:   `{"query":"{ average { min max difference } }"}`,
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				scaleMock.EXPECT().GetSummary(gomock.Any(), "", "").Return(&domain.ScaleSummary{
+					Count:   1,
+					Average: &domain.ScaleAverrage{Min: 47, Max: 50, Difference: 3},
+				}, nil)
+			},
+		},
+		{
+			name: "graphql mutation by get",
+			args: args{
+				method: http.MethodGet,
+				target: "/graphql?query=" + url.QueryEscape(`mutation { deleteScale(date: "2022-02-01") }`),
+				path:   "/graphql",
+			},
+			wantCode: http.StatusMethodNotAllowed,
+			mock:     func() {},
+		},
+		{
+			name: "chart error param",
+			args: args{
+				method: http.MethodGet,
+				target: "/scales/chart.svg?goal=goal",
+				path:   "/scales/chart.svg",
+			},
+			wantCode: http.StatusBadRequest,
+			mock:     func() {},
+		},
+		{
+			name: "report error param",
+			args: args{
+				method: http.MethodGet,
+				target: "/scales/report.pdf",
+				path:   "/scales/report.pdf",
+			},
+			wantCode: http.StatusBadRequest,
+			mock:     func() {},
+		},
+		{
+			name: "get history",
+			args: args{
+				method: http.MethodGet,
+				target: "/v2/scales/2022-02-01/history",
+				path:   "/v2/scales/{date}/history",
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				scaleMock.EXPECT().GetHistory(gomock.Any(), "2022-02-01").Return([]domain.ScaleHistory{
+					{
+						Date:      date,
+						Action:    domain.ScaleCreated,
+						Before:    []domain.Scale{},
+						After:     []domain.Scale{{Date: date, Min: 47, Max: 50, Difference: 3}},
+						ChangedAt: date,
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "get trash",
+			args: args{
+				method: http.MethodGet,
+				target: "/scales/trash",
+				path:   "/scales/trash",
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				scaleMock.EXPECT().GetTrash(gomock.Any()).Return([]domain.Scale{
+					{
+						Date:       date,
+						Min:        47,
+						Max:        50,
+						Difference: 3,
+						DeletedAt:  &date,
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "restore not found",
+			args: args{
+				method: http.MethodPost,
+				target: "/v2/scales/2022-02-01/restore",
+				path:   "/v2/scales/{date}/restore",
+			},
+			wantCode: http.StatusNotFound,
+			mock: func() {
+				scaleMock.EXPECT().Restore(gomock.Any(), "2022-02-01").Return(domain.ErrNotFound)
+			},
+		},
+		{
+			name: "batch scales",
+			args: args{
+				method: http.MethodPost,
+				target: "/v2/scales/batch",
+				path:   "/v2/scales/batch",
+				body:   `{"operations":[{"op":"create","date":"2022-02-01","min":45,"max":50}]}`,
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				scaleMock.EXPECT().Batch(gomock.Any(), gomock.Any()).Return([]domain.ScaleOperationResult{
+					{Op: domain.ScaleOperationCreate, Date: "2022-02-01", Scale: &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1}},
+				}, nil)
+			},
+		},
+		{
+			name: "batch scales rollback",
+			args: args{
+				method: http.MethodPost,
+				target: "/scales/batch",
+				path:   "/scales/batch",
+				body:   `{"operations":[{"op":"create","date":"2022-02-01","min":45,"max":50},{"op":"delete","date":"2022-02-02","version":1}]}`,
+			},
+			wantCode: http.StatusPreconditionFailed,
+			mock: func() {
+				scaleMock.EXPECT().Batch(gomock.Any(), gomock.Any()).Return([]domain.ScaleOperationResult{
+					{Op: domain.ScaleOperationCreate, Date: "2022-02-01"},
+					{Op: domain.ScaleOperationDelete, Date: "2022-02-02", Error: domain.ErrPreconditionFailed.Error()},
+				}, domain.ErrPreconditionFailed)
+			},
+		},
+		{
+			name: "stream error param",
+			args: args{
+				method: http.MethodGet,
+				target: "/scales/stream?last_event_id=id",
+				path:   "/scales/stream",
+			},
+			wantCode: http.StatusBadRequest,
+			mock:     func() {},
+		},
+		{
+			name: "create webhook",
+			args: args{
+				method: http.MethodPost,
+				target: "/webhook",
+				path:   "/webhook",
+				body:   `{"url":"http://localhost/hook","events":["scale.created"]}`,
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				webhookMock.EXPECT().Create(gomock.Any()).Return(&domain.Webhook{
+					ID:        "1",
+					URL:       "http://localhost/hook",
+					Secret:    "secret",
+					Events:    []string{"scale.created"},
+					CreatedAt: date,
+				}, nil)
+			},
+		},
+		{
+			name: "create webhook error param",
+			args: args{
+				method: http.MethodPost,
+				target: "/webhook",
+				path:   "/webhook",
+				body:   `{"url":"localhost"}`,
+			},
+			wantCode: http.StatusBadRequest,
+			mock:     func() {},
+		},
+		{
+			name: "get webhooks",
+			args: args{
+				method: http.MethodGet,
+				target: "/webhooks",
+				path:   "/webhooks",
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				webhookMock.EXPECT().GetWebhooks().Return([]domain.Webhook{
+					{
+						ID:        "1",
+						URL:       "http://localhost/hook",
+						CreatedAt: date,
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "delete webhook",
+			args: args{
+				method: http.MethodDelete,
+				target: "/webhook?id=1",
+				path:   "/webhook",
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				webhookMock.EXPECT().Delete("1").Return(nil)
+			},
+		},
+		{
+			name: "get webhook deliveries",
+			args: args{
+				method: http.MethodGet,
+				target: "/webhook/deliveries?id=1",
+				path:   "/webhook/deliveries",
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				webhookMock.EXPECT().GetDeliveries("1").Return([]domain.WebhookDelivery{
+					{
+						ID:         "a",
+						WebhookID:  "1",
+						Event:      domain.ScaleCreated,
+						Attempt:    1,
+						StatusCode: http.StatusOK,
+						Success:    true,
+						CreatedAt:  date,
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "get webhook deliveries not found",
+			args: args{
+				method: http.MethodGet,
+				target: "/webhook/deliveries?id=2",
+				path:   "/webhook/deliveries",
+			},
+			wantCode: http.StatusNotFound,
+			mock: func() {
+				webhookMock.EXPECT().GetDeliveries("2").Return(nil, domain.ErrNotFound)
+			},
+		},
+		{
+			name: "liveness",
+			args: args{
+				method: http.MethodGet,
+				target: "/healthz",
+				path:   "/healthz",
+			},
+			wantCode: http.StatusOK,
+			mock:     func() {},
+		},
+		{
+			name: "readiness",
+			args: args{
+				method: http.MethodGet,
+				target: "/readyz",
+				path:   "/readyz",
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				healthMock.EXPECT().Check(gomock.Any()).Return(&domain.HealthReport{
+					Status: domain.HealthUp,
+					Components: map[string]domain.ComponentHealth{
+						"scale_repository": {Status: domain.HealthUp},
+					},
+				})
+			},
+		},
+		{
+			name: "readiness draining",
+			args: args{
+				method: http.MethodGet,
+				target: "/readyz",
+				path:   "/readyz",
+			},
+			wantCode: http.StatusServiceUnavailable,
+			mock: func() {
+				healthMock.EXPECT().Check(gomock.Any()).Return(&domain.HealthReport{
+					Status: domain.HealthDraining,
+					Components: map[string]domain.ComponentHealth{
+						"scale_repository": {Status: domain.HealthDown, Error: "context deadline exceeded"},
+					},
+				})
+			},
+		},
+		{
+			name: "backup",
+			args: args{
+				method: http.MethodPost,
+				target: "/admin/backup",
+				path:   "/admin/backup",
+				token:  adminToken,
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				backupMock.EXPECT().Backup(gomock.Any()).Return(&domain.ScaleBackup{
+					Version:   domain.ScaleBackupVersion,
+					CreatedAt: date,
+					Checksum:  "sha256:abc",
+					Snapshot: &domain.ScaleSnapshot{
+						Scales: []domain.Scale{{Date: date, Min: 47, Max: 50, Difference: 3, Version: 1}},
+						History: []domain.ScaleHistory{
+							{
+								Date:      date,
+								Action:    domain.ScaleCreated,
+								Actor:     "budi",
+								Before:    []domain.Scale{},
+								After:     []domain.Scale{{Date: date, Min: 47, Max: 50, Difference: 3, Version: 1}},
+								ChangedAt: date,
+							},
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "backup unauthorized",
+			args: args{
+				method: http.MethodPost,
+				target: "/admin/backup",
+				path:   "/admin/backup",
+				token:  "guess",
+			},
+			wantCode: http.StatusUnauthorized,
+			mock:     func() {},
+		},
+		{
+			name: "restore",
+			args: args{
+				method: http.MethodPost,
+				target: "/admin/restore",
+				path:   "/admin/restore",
+				body:   `{"version":1,"created_at":"2022-02-01T07:00:00+07:00","scales":[]}`,
+				token:  adminToken,
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				backupMock.EXPECT().Restore(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "restore invalid",
+			args: args{
+				method: http.MethodPost,
+				target: "/admin/restore",
+				path:   "/admin/restore",
+				body:   `{"version":1,"created_at":"2022-02-01T07:00:00+07:00","scales":[]}`,
+				token:  adminToken,
+			},
+			wantCode: http.StatusBadRequest,
+			mock: func() {
+				backupMock.EXPECT().Restore(gomock.Any(), gomock.Any()).Return(&maintenance.InvalidBackupError{Problems: []maintenance.Problem{
+					{Date: "2022-02-01", Version: 1, Reason: "max 45 is below min 50"},
+				}})
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.args.method, test.args.target, strings.NewReader(test.args.body))
+			req.Header.Set("content-type", "application/json")
+			if test.args.token != "" {
+				req.Header.Set(echo.HeaderAuthorization, "Bearer "+test.args.token)
+			}
+			rec := httptest.NewRecorder()
+
+			test.mock()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.wantCode, rec.Code)
+			assert.NoError(t, validateResponse(test.args.method, test.args.path, rec.Code, rec.Body.Bytes()))
+		})
+	}
+}
+
+func TestValidateResponseDrift(t *testing.T) {
+	type args struct {
+		method string
+		path   string
+		status int
+		body   string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				method: http.MethodGet,
+				path:   "/scale",
+				status: http.StatusOK,
+				body:   `{"code":200,"message":"Success get scale","data":[{"date":"2022-02-01T00:00:00+07:00","min":47,"max":50,"difference":3,"version":0}],"errors":null}`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "undocumented property",
+			args: args{
+				method: http.MethodGet,
+				path:   "/scale",
+				status: http.StatusOK,
+				body:   `{"code":200,"message":"Success get scale","data":[{"date":"2022-02-01T00:00:00+07:00","min":47,"max":50,"difference":3,"version":0,"unit":"kg"}],"errors":null}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong type",
+			args: args{
+				method: http.MethodGet,
+				path:   "/scales",
+				status: http.StatusOK,
+				body:   `{"code":200,"message":"Success get scales","data":{"scales":[],"average":{"min":"47","max":50,"difference":3}},"errors":null}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing property",
+			args: args{
+				method: http.MethodPost,
+				path:   "/scale",
+				status: http.StatusOK,
+				body:   `{"code":200,"message":"Success create scale","data":null}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "undocumented path",
+			args: args{
+				method: http.MethodPut,
+				path:   "/scale",
+				status: http.StatusOK,
+				body:   `{}`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateResponse(test.args.method, test.args.path, test.args.status, []byte(test.args.body))
+			assert.Equal(t, test.wantErr, err != nil)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/scale/src/common"
+)
+
+// validateResponse checks a JSON response body against the schema documented
+// for the operation, path is the OpenAPI path template e.g. /scale.
+func validateResponse(method, path string, status int, body []byte) error {
+	spec, err := decode(document)
+	if err != nil {
+		return err
+	}
+
+	operation, ok := operation(spec, method, path)
+	if !ok {
+		return fmt.Errorf("%s %s is not documented", method, path)
+	}
+	responses, _ := operation["responses"].(map[string]interface{})
+	response, ok := responses[strconv.Itoa(status)]
+	if !ok {
+		response, ok = responses["default"]
+	}
+	if !ok {
+		return fmt.Errorf("%s %s does not document status %d", method, path, status)
+	}
+
+	response = resolve(spec, response)
+	schema := lookup(response, "content", echo.MIMEApplicationJSON, "schema")
+	if schema == nil {
+		return fmt.Errorf("%s %s status %d does not document a JSON body", method, path, status)
+	}
+
+	value, err := decode(body)
+	if err != nil {
+		return err
+	}
+	return validate(spec, schema, value, "$")
+}
+
+func decode(data []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	value := map[string]interface{}{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func lookup(value interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+func resolve(spec map[string]interface{}, value interface{}) interface{} {
+	for {
+		ref, ok := lookup(value, "$ref").(string)
+		if !ok {
+			return value
+		}
+		keys := strings.Split(strings.TrimPrefix(ref, "#/"), "/")
+		for i, key := range keys {
+			keys[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(key)
+		}
+		value = lookup(spec, keys...)
+	}
+}
+
+func operation(spec map[string]interface{}, method, path string) (map[string]interface{}, bool) {
+	item := resolve(spec, lookup(spec, "paths", path))
+	operation, ok := lookup(item, strings.ToLower(method)).(map[string]interface{})
+	return operation, ok
+}
+
+func validate(spec map[string]interface{}, schema, value interface{}, at string) error {
+	object, _ := resolve(spec, schema).(map[string]interface{})
+	if object == nil {
+		return fmt.Errorf("%s: unresolved schema", at)
+	}
+
+	if allOf, ok := object["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			if err := validate(spec, sub, value, at); err != nil {
+				return err
+			}
+		}
+	}
+
+	kind, _ := object["type"].(string)
+	if value == nil {
+		if nullable, _ := object["nullable"].(bool); nullable || kind == "" {
+			return nil
+		}
+		return fmt.Errorf("%s: null is not allowed", at)
+	}
+
+	switch kind {
+	case "":
+		return nil
+	case "object":
+		return validateObject(spec, object, value, at)
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected array", at)
+		}
+		for i, item := range items {
+			if err := validate(spec, object["items"], item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected string", at)
+		}
+		return validateFormat(object, s, at)
+	case "integer":
+		n, ok := value.(json.Number)
+		if _, err := n.Int64(); !ok || err != nil {
+			return fmt.Errorf("%s: expected integer", at)
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return fmt.Errorf("%s: expected number", at)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean", at)
+		}
+	default:
+		return fmt.Errorf("%s: unsupported type %s", at, kind)
+	}
+
+	return nil
+}
+
+func validateObject(spec map[string]interface{}, schema map[string]interface{}, value interface{}, at string) error {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: expected object", at)
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	required, _ := schema["required"].([]interface{})
+	for _, name := range required {
+		if _, ok := object[name.(string)]; !ok {
+			return fmt.Errorf("%s: missing required property %s", at, name)
+		}
+	}
+
+	for name, property := range object {
+		sub, ok := properties[name]
+		if !ok {
+			if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+				return fmt.Errorf("%s: undocumented property %s", at, name)
+			}
+			continue
+		}
+		if err := validate(spec, sub, property, at+"."+name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateFormat(schema map[string]interface{}, value, at string) error {
+	var err error
+	switch schema["format"] {
+	case "date":
+		_, err = time.Parse(common.TimeLayout, value)
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, value)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %v", at, err)
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: scale.proto
+
+package scalepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ScaleServiceClient is the client API for ScaleService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ScaleServiceClient interface {
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	GetScales(ctx context.Context, in *GetScalesRequest, opts ...grpc.CallOption) (*GetScalesResponse, error)
+	GetScale(ctx context.Context, in *GetScaleRequest, opts ...grpc.CallOption) (*GetScaleResponse, error)
+	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// ListScales streams every reading, newest first.
+	ListScales(ctx context.Context, in *ListScalesRequest, opts ...grpc.CallOption) (ScaleService_ListScalesClient, error)
+}
+
+type scaleServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScaleServiceClient(cc grpc.ClientConnInterface) ScaleServiceClient {
+	return &scaleServiceClient{cc}
+}
+
+func (c *scaleServiceClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
+	out := new(CreateResponse)
+	err := c.cc.Invoke(ctx, "/scale.v1.ScaleService/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scaleServiceClient) GetScales(ctx context.Context, in *GetScalesRequest, opts ...grpc.CallOption) (*GetScalesResponse, error) {
+	out := new(GetScalesResponse)
+	err := c.cc.Invoke(ctx, "/scale.v1.ScaleService/GetScales", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scaleServiceClient) GetScale(ctx context.Context, in *GetScaleRequest, opts ...grpc.CallOption) (*GetScaleResponse, error) {
+	out := new(GetScaleResponse)
+	err := c.cc.Invoke(ctx, "/scale.v1.ScaleService/GetScale", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scaleServiceClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error) {
+	out := new(UpdateResponse)
+	err := c.cc.Invoke(ctx, "/scale.v1.ScaleService/Update", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scaleServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/scale.v1.ScaleService/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scaleServiceClient) ListScales(ctx context.Context, in *ListScalesRequest, opts ...grpc.CallOption) (ScaleService_ListScalesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ScaleService_ServiceDesc.Streams[0], "/scale.v1.ScaleService/ListScales", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &scaleServiceListScalesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ScaleService_ListScalesClient interface {
+	Recv() (*Scale, error)
+	grpc.ClientStream
+}
+
+type scaleServiceListScalesClient struct {
+	grpc.ClientStream
+}
+
+func (x *scaleServiceListScalesClient) Recv() (*Scale, error) {
+	m := new(Scale)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ScaleServiceServer is the server API for ScaleService service.
+// All implementations must embed UnimplementedScaleServiceServer
+// for forward compatibility
+type ScaleServiceServer interface {
+	Create(context.Context, *CreateRequest) (*CreateResponse, error)
+	GetScales(context.Context, *GetScalesRequest) (*GetScalesResponse, error)
+	GetScale(context.Context, *GetScaleRequest) (*GetScaleResponse, error)
+	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// ListScales streams every reading, newest first.
+	ListScales(*ListScalesRequest, ScaleService_ListScalesServer) error
+	mustEmbedUnimplementedScaleServiceServer()
+}
+
+// UnimplementedScaleServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedScaleServiceServer struct {
+}
+
+func (UnimplementedScaleServiceServer) Create(context.Context, *CreateRequest) (*CreateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedScaleServiceServer) GetScales(context.Context, *GetScalesRequest) (*GetScalesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScales not implemented")
+}
+func (UnimplementedScaleServiceServer) GetScale(context.Context, *GetScaleRequest) (*GetScaleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScale not implemented")
+}
+func (UnimplementedScaleServiceServer) Update(context.Context, *UpdateRequest) (*UpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedScaleServiceServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedScaleServiceServer) ListScales(*ListScalesRequest, ScaleService_ListScalesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListScales not implemented")
+}
+func (UnimplementedScaleServiceServer) mustEmbedUnimplementedScaleServiceServer() {}
+
+// UnsafeScaleServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScaleServiceServer will
+// result in compilation errors.
+type UnsafeScaleServiceServer interface {
+	mustEmbedUnimplementedScaleServiceServer()
+}
+
+func RegisterScaleServiceServer(s grpc.ServiceRegistrar, srv ScaleServiceServer) {
+	s.RegisterService(&ScaleService_ServiceDesc, srv)
+}
+
+func _ScaleService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScaleServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/scale.v1.ScaleService/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScaleServiceServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScaleService_GetScales_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScalesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScaleServiceServer).GetScales(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/scale.v1.ScaleService/GetScales",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScaleServiceServer).GetScales(ctx, req.(*GetScalesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScaleService_GetScale_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScaleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScaleServiceServer).GetScale(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/scale.v1.ScaleService/GetScale",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScaleServiceServer).GetScale(ctx, req.(*GetScaleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScaleService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScaleServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/scale.v1.ScaleService/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScaleServiceServer).Update(ctx, req.(*UpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScaleService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScaleServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/scale.v1.ScaleService/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScaleServiceServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScaleService_ListScales_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListScalesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ScaleServiceServer).ListScales(m, &scaleServiceListScalesServer{stream})
+}
+
+type ScaleService_ListScalesServer interface {
+	Send(*Scale) error
+	grpc.ServerStream
+}
+
+type scaleServiceListScalesServer struct {
+	grpc.ServerStream
+}
+
+func (x *scaleServiceListScalesServer) Send(m *Scale) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ScaleService_ServiceDesc is the grpc.ServiceDesc for ScaleService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ScaleService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "scale.v1.ScaleService",
+	HandlerType: (*ScaleServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _ScaleService_Create_Handler,
+		},
+		{
+			MethodName: "GetScales",
+			Handler:    _ScaleService_GetScales_Handler,
+		},
+		{
+			MethodName: "GetScale",
+			Handler:    _ScaleService_GetScale_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _ScaleService_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _ScaleService_Delete_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListScales",
+			Handler:       _ScaleService_ListScales_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "scale.proto",
+}
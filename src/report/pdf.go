@@ -0,0 +1,148 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+)
+
+// document is a minimal PDF 1.4 writer supporting the standard Helvetica
+// fonts and basic vector drawing, enough to render the progress report.
+type document struct {
+	width  float64
+	height float64
+	pages  []*page
+}
+
+// page coordinates have their origin at the top-left corner like the chart
+// layout, they are flipped when written to the content stream.
+type page struct {
+	height  float64
+	content bytes.Buffer
+}
+
+func newDocument(width, height float64) *document {
+	return &document{
+		width:  width,
+		height: height,
+	}
+}
+
+func (d *document) AddPage() *page {
+	p := &page{height: d.height}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+func (p *page) Text(x, y, size float64, font, text string) {
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, p.height-y, escape(text))
+}
+
+func (p *page) TextRight(x, y, size float64, font, text string) {
+	p.Text(x-textWidth(text, size), y, size, font, text)
+}
+
+func (p *page) Color(r, g, b float64) {
+	fmt.Fprintf(&p.content, "%.3f %.3f %.3f RG %.3f %.3f %.3f rg\n", r, g, b, r, g, b)
+}
+
+func (p *page) LineWidth(width float64) {
+	fmt.Fprintf(&p.content, "%.2f w\n", width)
+}
+
+func (p *page) Dash(on, off float64) {
+	if on == 0 {
+		p.content.WriteString("[] 0 d\n")
+		return
+	}
+	fmt.Fprintf(&p.content, "[%.1f %.1f] 0 d\n", on, off)
+}
+
+func (p *page) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "%.2f %.2f m %.2f %.2f l S\n", x1, p.height-y1, x2, p.height-y2)
+}
+
+func (p *page) Rect(x, y, width, height float64) {
+	fmt.Fprintf(&p.content, "%.2f %.2f %.2f %.2f re f\n", x, p.height-y-height, width, height)
+}
+
+func (p *page) Polyline(xs, ys []float64) {
+	p.path(xs, ys)
+	p.content.WriteString("S\n")
+}
+
+func (p *page) Polygon(xs, ys []float64) {
+	p.path(xs, ys)
+	p.content.WriteString("h f\n")
+}
+
+func (p *page) path(xs, ys []float64) {
+	for i := range xs {
+		op := "l"
+		if i == 0 {
+			op = "m"
+		}
+		fmt.Fprintf(&p.content, "%.2f %.2f %s ", xs[i], p.height-ys[i], op)
+	}
+}
+
+func (d *document) Bytes() []byte {
+	buf := &bytes.Buffer{}
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// objects 1-4 are fixed, each page then takes a page and a content object
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, p := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			d.width, d.height, fontRegular, fontBold, 6+i*2))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+// escape converts the text to WinAnsiEncoding, runes outside Latin-1 are
+// replaced since the standard fonts cannot render them.
+func escape(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\r", "", "\n", " ")
+	encoded := make([]byte, 0, len(text))
+	for _, r := range replacer.Replace(text) {
+		if r > 0xff {
+			r = '?'
+		}
+		encoded = append(encoded, byte(r))
+	}
+	return string(encoded)
+}
+
+// textWidth approximates the Helvetica advance width, digits and most lower
+// case letters are close to half of the font size.
+func textWidth(text string, size float64) float64 {
+	return float64(len(text)) * size * 0.52
+}
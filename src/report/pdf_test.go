@@ -0,0 +1,81 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentBytes(t *testing.T) {
+	tests := []struct {
+		name      string
+		pages     int
+		wantCount string
+	}{
+		{
+			name:      "single page",
+			pages:     1,
+			wantCount: "/Count 1",
+		},
+		{
+			name:      "multiple pages",
+			pages:     3,
+			wantCount: "/Count 3",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc := newDocument(pageWidth, pageHeight)
+			for i := 0; i < test.pages; i++ {
+				p := doc.AddPage()
+				p.Text(margin, 50, 12, fontRegular, fmt.Sprintf("page (%d)", i))
+				p.Polygon([]float64{0, 10, 10}, []float64{0, 0, 10})
+			}
+
+			got := doc.Bytes()
+			assert.True(t, bytes.HasPrefix(got, []byte("%PDF-1.4\n")))
+			assert.True(t, bytes.HasSuffix(got, []byte("%%EOF\n")))
+			assert.Contains(t, string(got), test.wantCount)
+			assert.Contains(t, string(got), `(page \(0\)) Tj`)
+
+			startxref := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(got)
+			if assert.NotNil(t, startxref) {
+				offset, _ := strconv.Atoi(string(startxref[1]))
+				assert.True(t, bytes.HasPrefix(got[offset:], []byte("xref\n")))
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       string
+		wantResult string
+	}{
+		{
+			name:       "parentheses",
+			args:       `a(b)\c`,
+			wantResult: `a\(b\)\\c`,
+		},
+		{
+			name:       "latin-1",
+			args:       "é",
+			wantResult: "\xe9",
+		},
+		{
+			name:       "unsupported",
+			args:       "日",
+			wantResult: "?",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.wantResult, escape(test.args))
+		})
+	}
+}
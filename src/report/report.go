@@ -0,0 +1,142 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scale/src/chart"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+)
+
+const (
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 40
+	rowHeight  = 16
+)
+
+// Report.Scales are the readings of Month newest first and Average theirs, nil
+// without readings.
+type Report struct {
+	User    string
+	Month   time.Time
+	Scales  []domain.Scale
+	Average *domain.ScaleAverrage
+}
+
+// MonthRange returns the first and last day of month.
+func MonthRange(month time.Time) (time.Time, time.Time) {
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	return from, from.AddDate(0, 1, -1)
+}
+
+func PDF(r *Report) []byte {
+	from, to := MonthRange(r.Month)
+	scales := r.Scales
+	title := fmt.Sprintf("%s %d", helper.MonthName(r.Month), r.Month.Year())
+
+	doc := newDocument(pageWidth, pageHeight)
+	p := doc.AddPage()
+
+	p.Text(margin, 50, 18, fontBold, "Weight Report - "+title)
+	if r.User != "" {
+		p.Text(margin, 70, 11, fontRegular, "User: "+r.User)
+	}
+	p.Text(margin, 86, 11, fontRegular, "Generated: "+helper.FormatDate(helper.Now()))
+
+	drawChart(p, margin, 100, chart.NewLayout(scales, chart.Options{
+		From:   from,
+		To:     to,
+		Width:  pageWidth - 2*margin,
+		Height: 220,
+	}))
+
+	y := 345.0
+	// left out without readings to average
+	if average := r.Average; average != nil {
+		p.Color(0, 0, 0)
+		p.Text(margin, y, 11, fontBold, "Average")
+		p.Text(margin+120, y, 11, fontRegular, fmt.Sprintf("Min %.1f   Max %.1f   Difference %.1f", average.Min, average.Max, average.Difference))
+		y += 25
+	}
+
+	y = tableHeader(p, y)
+	for _, scale := range scales {
+		if y > pageHeight-margin {
+			p = doc.AddPage()
+			y = tableHeader(p, 50)
+		}
+		p.Text(margin+4, y, 10, fontRegular, helper.FormatDate(scale.Date))
+		p.TextRight(340, y, 10, fontRegular, fmt.Sprint(scale.Min))
+		p.TextRight(430, y, 10, fontRegular, fmt.Sprint(scale.Max))
+		p.TextRight(pageWidth-margin-4, y, 10, fontRegular, fmt.Sprint(scale.Difference))
+		y += rowHeight
+	}
+	if len(scales) == 0 {
+		p.Text(margin+4, y, 10, fontRegular, "No readings for "+title)
+	}
+
+	return doc.Bytes()
+}
+
+func tableHeader(p *page, y float64) float64 {
+	p.Color(0.9, 0.9, 0.9)
+	p.Rect(margin, y-12, pageWidth-2*margin, rowHeight)
+	p.Color(0, 0, 0)
+	p.Text(margin+4, y, 10, fontBold, "Date")
+	p.TextRight(340, y, 10, fontBold, "Min")
+	p.TextRight(430, y, 10, fontBold, "Max")
+	p.TextRight(pageWidth-margin-4, y, 10, fontBold, "Difference")
+	return y + rowHeight + 2
+}
+
+func drawChart(p *page, x, y float64, layout *chart.Layout) {
+	p.LineWidth(0.5)
+	p.Color(0.6, 0.6, 0.6)
+	p.Line(x+layout.Left, y+layout.Top, x+layout.Left, y+layout.Bottom)
+	p.Line(x+layout.Left, y+layout.Bottom, x+layout.Right, y+layout.Bottom)
+	if layout.Empty() {
+		p.Text(x+float64(layout.Width)/2-20, y+float64(layout.Height)/2, 10, fontRegular, "No data")
+		return
+	}
+
+	for _, tick := range layout.YTicks {
+		p.Color(0.93, 0.93, 0.93)
+		p.Line(x+layout.Left, y+tick.Pos, x+layout.Right, y+tick.Pos)
+		p.Color(0.4, 0.4, 0.4)
+		p.TextRight(x+layout.Left-4, y+tick.Pos+3, 8, fontRegular, tick.Label)
+	}
+	for _, tick := range layout.XTicks {
+		p.Text(x+tick.Pos-textWidth(tick.Label, 8)/2, y+layout.Bottom+14, 8, fontRegular, tick.Label)
+	}
+
+	band := append(append([]chart.Point{}, layout.Max...), reversed(layout.Min)...)
+	p.Color(0.85, 0.89, 0.94)
+	p.Polygon(coords(x, y, band))
+
+	p.LineWidth(1.5)
+	p.Color(0.88, 0.34, 0.35)
+	p.Polyline(coords(x, y, layout.Max))
+	p.Color(0.31, 0.47, 0.65)
+	p.Polyline(coords(x, y, layout.Min))
+	p.LineWidth(0.5)
+}
+
+func coords(x, y float64, points []chart.Point) ([]float64, []float64) {
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, point := range points {
+		xs[i] = x + point.X
+		ys[i] = y + point.Y
+	}
+	return xs, ys
+}
+
+func reversed(points []chart.Point) []chart.Point {
+	result := make([]chart.Point, len(points))
+	for i, point := range points {
+		result[len(points)-1-i] = point
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	helper.InitTime()
+}
+
+func TestPDF(t *testing.T) {
+	month := time.Date(2018, 8, 1, 0, 0, 0, 0, helper.GetLocation())
+
+	tests := []struct {
+		name       string
+		args       *Report
+		wantResult []string
+		notResult  []string
+	}{
+		{
+			name: "success",
+			args: &Report{
+				User:  "Budi",
+				Month: month,
+				Scales: []domain.Scale{
+					{
+						Date:       time.Date(2018, 8, 22, 0, 0, 0, 0, helper.GetLocation()),
+						Min:        49,
+						Max:        50,
+						Difference: 1,
+					},
+				},
+				Average: &domain.ScaleAverrage{
+					Min:        49,
+					Max:        50,
+					Difference: 1,
+				},
+			},
+			wantResult: []string{"Weight Report - Agustus 2018", "User: Budi", "Rabu, 22 Agustus 2018", "Min 49.0   Max 50.0   Difference 1.0"},
+			notResult:  []string{"No readings", "NaN"},
+		},
+		{
+			name: "no readings",
+			args: &Report{
+				Month: month,
+			},
+			wantResult: []string{"No readings for Agustus 2018", "No data"},
+			notResult:  []string{"User:", "Average"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := string(PDF(test.args))
+			for _, want := range test.wantResult {
+				assert.Contains(t, got, want)
+			}
+			for _, not := range test.notResult {
+				assert.NotContains(t, got, not)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"net"
+
+	"github.com/labstack/echo"
+	"github.com/scale/src/helper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// Actor records who makes the request in its context for the change history.
+// Without authenticated users that is the address the connection comes from,
+// unlike a header or X-Forwarded-For the client cannot choose it.
+func Actor(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		c.SetRequest(req.WithContext(helper.WithActor(req.Context(), hostOnly(req.RemoteAddr))))
+		return next(c)
+	}
+}
+
+// ActorInterceptor is Actor for gRPC, the peer address.
+func ActorInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(helper.WithActor(ctx, peerHost(ctx)), req)
+}
+
+// ActorStreamInterceptor is ActorInterceptor for streaming calls.
+func ActorStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := ss.Context()
+	return handler(srv, &serverStream{ServerStream: ss, ctx: helper.WithActor(ctx, peerHost(ctx))})
+}
+
+// serverStream hands the stream handler the context an interceptor added to.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// peerHost is the address of the gRPC peer of ctx without the port, empty
+// when unknown.
+func peerHost(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		return hostOnly(p.Addr.String())
+	}
+	return ""
+}
+
+// hostOnly drops the port of addr, it changes with every connection.
+func hostOnly(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/scale/src/helper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestActor(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       string
+		wantResult string
+	}{
+		{
+			name:       "client address",
+			wantResult: "192.0.2.1",
+		},
+		{
+			name:       "header ignored",
+			args:       "budi",
+			wantResult: "192.0.2.1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.args != "" {
+				req.Header.Set("X-Actor", test.args)
+				req.Header.Set(echo.HeaderXForwardedFor, test.args)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var got string
+			err := Actor(func(c echo.Context) error {
+				got = helper.GetActor(c.Request().Context())
+				return nil
+			})(c)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantResult, got)
+		})
+	}
+}
+
+func TestActorInterceptor(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}
+
+	tests := []struct {
+		name       string
+		args       context.Context
+		wantResult string
+	}{
+		{
+			name:       "peer address",
+			args:       peer.NewContext(context.Background(), &peer.Peer{Addr: addr}),
+			wantResult: "192.0.2.1",
+		},
+		{
+			name:       "metadata ignored",
+			args:       metadata.NewIncomingContext(peer.NewContext(context.Background(), &peer.Peer{Addr: addr}), metadata.Pairs("x-actor", "budi")),
+			wantResult: "192.0.2.1",
+		},
+		{
+			name:       "unknown",
+			args:       context.Background(),
+			wantResult: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got string
+			_, err := ActorInterceptor(test.args, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				got = helper.GetActor(ctx)
+				return nil, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantResult, got)
+		})
+	}
+}
+
+func TestActorStreamInterceptor(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}})
+
+	var got string
+	err := ActorStreamInterceptor(nil, &serverStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(srv interface{}, ss grpc.ServerStream) error {
+		got = helper.GetActor(ss.Context())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "192.0.2.1", got)
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/scale/src/common"
+	"github.com/scale/src/helper"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultBodyLimit caps the bodies of batches and gRPC messages
+	DefaultBodyLimit = 1 << 20
+	scaleBodyLimit   = 4 << 10
+	graphQLBodyLimit = 64 << 10
+
+	minClientIdle = 10 * time.Minute
+)
+
+// probes and scrapes come from the platform, limiting them would only take
+// a busy instance out of rotation
+var unlimitedRoutes = map[string]bool{
+	"/ping":    true,
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+type client struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// RateLimiter hands every client a token bucket refilled at limit requests
+// per second up to burst. A client is its X-API-Key when it sends one, over
+// HTTP and gRPC alike, and its connection address otherwise. Behind a trusted
+// proxy the HTTP address is taken from X-Forwarded-For or X-Real-IP instead,
+// the proxy sets them.
+type RateLimiter struct {
+	limit      rate.Limit
+	burst      int
+	trustProxy bool
+	// a client idle this long has a full bucket again and can be forgotten
+	idle time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	clients map[string]*client
+	swept   time.Time
+}
+
+func NewRateLimiter(limit float64, burst int, trustProxy bool) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	idle := minClientIdle
+	if limit > 0 {
+		if refill := time.Duration(float64(burst) / limit * float64(time.Second)); refill > idle {
+			idle = refill
+		}
+	}
+	return &RateLimiter{
+		limit:      rate.Limit(limit),
+		burst:      burst,
+		trustProxy: trustProxy,
+		idle:       idle,
+		now:        time.Now,
+		clients:    map[string]*client{},
+	}
+}
+
+// allow takes a token from the bucket of key, returning how long to wait for
+// one when it is empty.
+func (l *RateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.swept) > l.idle {
+		for k, c := range l.clients {
+			if now.Sub(c.seen) > l.idle {
+				delete(l.clients, k)
+			}
+		}
+		l.swept = now
+	}
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &client{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[key] = c
+	}
+	c.seen = now
+
+	r := c.limiter.ReserveN(now, 1)
+	delay := r.DelayFrom(now)
+	if delay == 0 {
+		return true, 0
+	}
+	r.CancelAt(now)
+	return false, delay
+}
+
+func retryAfter(delay time.Duration) int {
+	return int(math.Ceil(delay.Seconds()))
+}
+
+// RateLimit answers 429 with a Retry-After header once the client emptied its
+// bucket.
+func (l *RateLimiter) RateLimit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if unlimitedRoutes[c.Path()] {
+			return next(c)
+		}
+
+		ok, delay := l.allow(l.clientKey(c))
+		if !ok {
+			seconds := retryAfter(delay)
+			c.Response().Header().Set(common.HeaderRetryAfter, strconv.Itoa(seconds))
+			code := http.StatusTooManyRequests
+			return c.JSON(code, helper.Response(code, "Too many requests", nil, fmt.Sprintf("rate limit exceeded, retry in %ds", seconds)))
+		}
+		return next(c)
+	}
+}
+
+// RateLimitInterceptor is RateLimit for gRPC, answering ResourceExhausted
+// with a retry-after trailer.
+func (l *RateLimiter) RateLimitInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	err := l.allowGRPC(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// RateLimitStreamInterceptor is RateLimitInterceptor for streaming calls, a
+// stream takes one token when it starts.
+func (l *RateLimiter) RateLimitStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	err := l.allowGRPC(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+func (l *RateLimiter) allowGRPC(ctx context.Context) error {
+	ok, delay := l.allow(grpcClientKey(ctx))
+	if !ok {
+		seconds := retryAfter(delay)
+		grpc.SetTrailer(ctx, metadata.Pairs(common.HeaderRetryAfter, strconv.Itoa(seconds)))
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry in %ds", seconds)
+	}
+	return nil
+}
+
+// clientKey is the bucket of the HTTP request of c, prefixed so an API key
+// cannot share the bucket of an address.
+func (l *RateLimiter) clientKey(c echo.Context) string {
+	req := c.Request()
+	if apiKey := req.Header.Get(common.HeaderAPIKey); apiKey != "" {
+		return "key:" + apiKey
+	}
+	if l.trustProxy {
+		return "ip:" + c.RealIP()
+	}
+	return "ip:" + hostOnly(req.RemoteAddr)
+}
+
+// grpcClientKey is clientKey for gRPC, the x-api-key metadata or the peer
+// address.
+func grpcClientKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(common.HeaderAPIKey); len(values) > 0 && values[0] != "" {
+		return "key:" + values[0]
+	}
+	return "ip:" + peerHost(ctx)
+}
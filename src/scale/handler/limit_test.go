@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/scale/src/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimit(t *testing.T) {
+	now := time.Date(2022, 2, 1, 7, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(0.5, 2, false)
+	limiter.now = func() time.Time {
+		return now
+	}
+
+	e := echo.New()
+	e.Use(limiter.RateLimit)
+	e.GET("/scales", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/healthz", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	type args struct {
+		path       string
+		remoteAddr string
+		header     string
+		after      time.Duration
+	}
+	tests := []struct {
+		name           string
+		args           args
+		wantCode       int
+		wantRetryAfter string
+		wantResult     string
+	}{
+		{
+			name:     "first",
+			args:     args{path: "/scales"},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "burst",
+			args:     args{path: "/scales"},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:           "limited",
+			args:           args{path: "/scales"},
+			wantCode:       http.StatusTooManyRequests,
+			wantRetryAfter: "2",
+			wantResult: `{"code":429,"message":"Too many requests","data":null,"errors":"rate limit exceeded, retry in 2s"}
+`,
+		},
+		{
+			name:           "forwarded header",
+			args:           args{path: "/scales", header: echo.HeaderXForwardedFor},
+			wantCode:       http.StatusTooManyRequests,
+			wantRetryAfter: "2",
+		},
+		{
+			name:     "api key",
+			args:     args{path: "/scales", header: common.HeaderAPIKey},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "api key elsewhere",
+			args:     args{path: "/scales", remoteAddr: "192.0.2.3:1234", header: common.HeaderAPIKey},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:           "api key limited",
+			args:           args{path: "/scales", remoteAddr: "192.0.2.4:1234", header: common.HeaderAPIKey},
+			wantCode:       http.StatusTooManyRequests,
+			wantRetryAfter: "2",
+		},
+		{
+			name:     "other client",
+			args:     args{path: "/scales", remoteAddr: "192.0.2.2:1234"},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "unlimited route",
+			args:     args{path: "/healthz"},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "refilled",
+			args:     args{path: "/scales", after: 2 * time.Second},
+			wantCode: http.StatusOK,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now = now.Add(test.args.after)
+			req := httptest.NewRequest(http.MethodGet, test.args.path, nil)
+			if test.args.remoteAddr != "" {
+				req.RemoteAddr = test.args.remoteAddr
+			}
+			if test.args.header != "" {
+				req.Header.Set(test.args.header, "198.51.100.1")
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.wantCode, rec.Code)
+			assert.Equal(t, test.wantRetryAfter, rec.Header().Get(common.HeaderRetryAfter))
+			if test.wantResult != "" {
+				assert.Equal(t, test.wantResult, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRateLimitTrustProxy(t *testing.T) {
+	limiter := NewRateLimiter(1, 1, true)
+
+	e := echo.New()
+	e.Use(limiter.RateLimit)
+	e.GET("/scales", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		args     string
+		wantCode int
+	}{
+		{
+			name:     "first",
+			args:     "198.51.100.1",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "limited",
+			args:     "198.51.100.1",
+			wantCode: http.StatusTooManyRequests,
+		},
+		{
+			name:     "other client behind the proxy",
+			args:     "198.51.100.2",
+			wantCode: http.StatusOK,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/scales", nil)
+			req.Header.Set(echo.HeaderXForwardedFor, test.args)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.wantCode, rec.Code)
+		})
+	}
+}
+
+func TestRateLimitSweep(t *testing.T) {
+	now := time.Date(2022, 2, 1, 7, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(10, 20, false)
+	limiter.now = func() time.Time {
+		return now
+	}
+
+	limiter.allow("192.0.2.1")
+	now = now.Add(time.Minute)
+	limiter.allow("192.0.2.2")
+	now = now.Add(minClientIdle)
+	limiter.allow("192.0.2.2")
+
+	assert.Len(t, limiter.clients, 1)
+	assert.Contains(t, limiter.clients, "192.0.2.2")
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	limiter := NewRateLimiter(1, 1, false)
+	addr := &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	tests := []struct {
+		name     string
+		args     context.Context
+		wantCode codes.Code
+	}{
+		{
+			name:     "first",
+			args:     peer.NewContext(context.Background(), &peer.Peer{Addr: addr}),
+			wantCode: codes.OK,
+		},
+		{
+			name:     "limited",
+			args:     peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: addr.IP, Port: 4321}}),
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name:     "api key",
+			args:     metadata.NewIncomingContext(peer.NewContext(context.Background(), &peer.Peer{Addr: addr}), metadata.Pairs("x-api-key", "key")),
+			wantCode: codes.OK,
+		},
+		{
+			name:     "api key limited",
+			args:     metadata.NewIncomingContext(peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.3"), Port: 1234}}), metadata.Pairs("x-api-key", "key")),
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name:     "other client",
+			args:     peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.2"), Port: 1234}}),
+			wantCode: codes.OK,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := limiter.RateLimitInterceptor(test.args, nil, &grpc.UnaryServerInfo{}, handler)
+			assert.Equal(t, test.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestRateLimitStreamInterceptor(t *testing.T) {
+	limiter := NewRateLimiter(1, 1, false)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}})
+	var calls int
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		calls++
+		return nil
+	}
+
+	err := limiter.RateLimitStreamInterceptor(nil, &serverStream{ctx: ctx}, &grpc.StreamServerInfo{}, handler)
+	assert.NoError(t, err)
+	err = limiter.RateLimitStreamInterceptor(nil, &serverStream{ctx: ctx}, &grpc.StreamServerInfo{}, handler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, 1, calls)
+}
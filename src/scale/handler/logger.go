@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/scale/src/helper"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const maxRequestIDLength = 128
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID keeps client IDs from breaking the logs or the header
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// responseStatus is the status the response to c ends up with, err not being
+// handled yet when returned by the next handler.
+func responseStatus(c echo.Context, err error) int {
+	if err == nil || c.Response().Committed {
+		return c.Response().Status
+	}
+	if httpErr, ok := err.(*echo.HTTPError); ok {
+		return httpErr.Code
+	}
+	return http.StatusInternalServerError
+}
+
+// NewRequestLogger tags every request with an X-Request-ID, the one sent by
+// the client when valid, puts logger carrying it in the request context and
+// logs the request once served.
+func NewRequestLogger(logger logrus.FieldLogger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			res := c.Response()
+			id := req.Header.Get(echo.HeaderXRequestID)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			res.Header().Set(echo.HeaderXRequestID, id)
+
+			logger := logger.WithField("request_id", id)
+			c.SetRequest(req.WithContext(helper.WithLogger(req.Context(), logger)))
+
+			start := time.Now()
+			err := next(c)
+
+			status := responseStatus(c, err)
+			entry := logger.WithFields(logrus.Fields{
+				"method":     req.Method,
+				"route":      c.Path(),
+				"uri":        req.RequestURI,
+				"status":     status,
+				"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+				"bytes_out":  res.Size,
+				"remote_ip":  c.RealIP(),
+			})
+			if err != nil {
+				entry = entry.WithError(err)
+			}
+			if status >= 500 {
+				entry.Error("request served")
+			} else {
+				entry.Info("request served")
+			}
+			return err
+		}
+	}
+}
+
+// NewRequestLoggerInterceptor is NewRequestLogger for gRPC, the ID comes from
+// and is sent back in the x-request-id metadata.
+func NewRequestLoggerInterceptor(logger logrus.FieldLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var res interface{}
+		err := serveLogged(ctx, logger, info.FullMethod, func(ctx context.Context) error {
+			var err error
+			res, err = handler(ctx, req)
+			return err
+		})
+		return res, err
+	}
+}
+
+// NewRequestLoggerStreamInterceptor is NewRequestLoggerInterceptor for
+// streaming calls, logged once the stream ends.
+func NewRequestLoggerStreamInterceptor(logger logrus.FieldLogger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return serveLogged(ss.Context(), logger, info.FullMethod, func(ctx context.Context) error {
+			return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		})
+	}
+}
+
+func serveLogged(ctx context.Context, logger logrus.FieldLogger, method string, serve func(ctx context.Context) error) error {
+	var id string
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(echo.HeaderXRequestID); len(values) > 0 {
+		id = values[0]
+	}
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(echo.HeaderXRequestID, id))
+
+	logger = logger.WithField("request_id", id)
+
+	start := time.Now()
+	err := serve(helper.WithLogger(ctx, logger))
+
+	code := status.Code(err)
+	entry := logger.WithFields(logrus.Fields{
+		"method":     method,
+		"code":       code.String(),
+		"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+	})
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	if code == codes.Internal || code == codes.Unknown {
+		entry.Error("request served")
+	} else {
+		entry.Info("request served")
+	}
+	return err
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/scale/src/helper"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      string
+		handler   echo.HandlerFunc
+		wantID    string
+		wantLevel logrus.Level
+		wantCode  int
+	}{
+		{
+			name: "propagated id",
+			args: "abc-123",
+			handler: func(c echo.Context) error {
+				helper.GetLogger(c.Request().Context()).Info("inside")
+				return c.NoContent(http.StatusNoContent)
+			},
+			wantID:    "abc-123",
+			wantLevel: logrus.InfoLevel,
+			wantCode:  http.StatusNoContent,
+		},
+		{
+			name: "invalid id",
+			args: "bad id\n",
+			handler: func(c echo.Context) error {
+				helper.GetLogger(c.Request().Context()).Info("inside")
+				return c.NoContent(http.StatusNoContent)
+			},
+			wantLevel: logrus.InfoLevel,
+			wantCode:  http.StatusNoContent,
+		},
+		{
+			name: "error",
+			handler: func(c echo.Context) error {
+				helper.GetLogger(c.Request().Context()).Info("inside")
+				return errors.New("some error")
+			},
+			wantLevel: logrus.ErrorLevel,
+			wantCode:  http.StatusInternalServerError,
+		},
+		{
+			name: "http error",
+			handler: func(c echo.Context) error {
+				helper.GetLogger(c.Request().Context()).Info("inside")
+				return echo.ErrForbidden
+			},
+			wantLevel: logrus.InfoLevel,
+			wantCode:  http.StatusForbidden,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			e := echo.New()
+			e.Use(NewRequestLogger(logger))
+			e.GET("/scales/:date", test.handler)
+			req := httptest.NewRequest(http.MethodGet, "/scales/2022-02-01", nil)
+			if test.args != "" {
+				req.Header.Set(echo.HeaderXRequestID, test.args)
+			}
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(echo.HeaderXRequestID)
+			if test.wantID != "" {
+				assert.Equal(t, test.wantID, id)
+			} else {
+				assert.Len(t, id, 32)
+			}
+			assert.Equal(t, test.wantCode, rec.Code)
+
+			entries := hook.AllEntries()
+			if assert.Len(t, entries, 2) {
+				assert.Equal(t, "inside", entries[0].Message)
+				assert.Equal(t, id, entries[0].Data["request_id"])
+
+				access := entries[1]
+				assert.Equal(t, test.wantLevel, access.Level)
+				assert.Equal(t, id, access.Data["request_id"])
+				assert.Equal(t, "/scales/:date", access.Data["route"])
+				assert.Equal(t, http.MethodGet, access.Data["method"])
+				assert.Equal(t, test.wantCode, access.Data["status"])
+				assert.Contains(t, access.Data, "latency_ms")
+			}
+		})
+	}
+}
+
+func TestRequestLoggerInterceptor(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      context.Context
+		err       error
+		wantID    string
+		wantLevel logrus.Level
+	}{
+		{
+			name:      "propagated id",
+			args:      metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "abc-123")),
+			wantID:    "abc-123",
+			wantLevel: logrus.InfoLevel,
+		},
+		{
+			name:      "not found",
+			args:      context.Background(),
+			err:       status.Error(codes.NotFound, "your requested item is not found"),
+			wantLevel: logrus.InfoLevel,
+		},
+		{
+			name:      "internal",
+			args:      context.Background(),
+			err:       status.Error(codes.Internal, "some error"),
+			wantLevel: logrus.ErrorLevel,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+
+			_, err := NewRequestLoggerInterceptor(logger)(test.args, nil, &grpc.UnaryServerInfo{FullMethod: "/scale.ScaleService/GetScales"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, test.err
+			})
+			assert.Equal(t, test.err, err)
+
+			entry := hook.LastEntry()
+			if assert.NotNil(t, entry) {
+				assert.Equal(t, test.wantLevel, entry.Level)
+				assert.Equal(t, "/scale.ScaleService/GetScales", entry.Data["method"])
+				assert.Equal(t, status.Code(test.err).String(), entry.Data["code"])
+				if test.wantID != "" {
+					assert.Equal(t, test.wantID, entry.Data["request_id"])
+				} else {
+					assert.Len(t, entry.Data["request_id"], 32)
+				}
+			}
+		})
+	}
+}
+
+func TestRequestLoggerStreamInterceptor(t *testing.T) {
+	logger, hook := newTestLogger()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "abc-123"))
+
+	err := NewRequestLoggerStreamInterceptor(logger)(nil, &serverStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/scale.ScaleService/ListScales"}, func(srv interface{}, ss grpc.ServerStream) error {
+		// handlers log with the request logger from the stream context
+		helper.GetLogger(ss.Context()).Info("streaming")
+		return status.Error(codes.Internal, "some error")
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	entries := hook.AllEntries()
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "abc-123", entries[0].Data["request_id"])
+		assert.Equal(t, logrus.ErrorLevel, entries[1].Level)
+		assert.Equal(t, "/scale.ScaleService/ListScales", entries[1].Data["method"])
+		assert.Equal(t, "abc-123", entries[1].Data["request_id"])
+	}
+}
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, validRequestID("0f8b3c1e-7a2d-4c55-9f0e-2b6d1a4c8e90"))
+	assert.False(t, validRequestID(""))
+	assert.False(t, validRequestID("with space"))
+	assert.False(t, validRequestID(strings.Repeat("a", maxRequestIDLength+1)))
+}
+
+func newTestLogger() (*logrus.Logger, *test.Hook) {
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+	return logger, hook
+}
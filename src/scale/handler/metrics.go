@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests served by route, method and status.",
+	}, []string{"route", "method", "status"})
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of HTTP requests by route and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+)
+
+// route returns the route pattern c matched, such as /scales/:date, empty when
+// nothing matched. echo then reports the raw request path, which is no route
+// and only comes with a 404.
+func route(c echo.Context, status int) string {
+	if status == http.StatusNotFound && !registered(c.Echo(), c.Path()) {
+		return ""
+	}
+	return c.Path()
+}
+
+func registered(e *echo.Echo, path string) bool {
+	for _, route := range e.Routes() {
+		if route.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Metrics counts and times every request by its route pattern, such as
+// /scales/:date, so the number of series stays bounded.
+func Metrics(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		err := next(c)
+
+		status := responseStatus(c, err)
+		route := route(c, status)
+		method := c.Request().Method
+		httpRequests.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+		httpDuration.WithLabelValues(route, method).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
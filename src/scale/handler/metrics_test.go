@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       echo.HandlerFunc
+		wantStatus string
+	}{
+		{
+			name: "success",
+			args: func(c echo.Context) error {
+				return c.NoContent(http.StatusNoContent)
+			},
+			wantStatus: "204",
+		},
+		{
+			name: "error",
+			args: func(c echo.Context) error {
+				return errors.New("some error")
+			},
+			wantStatus: "500",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(Metrics)
+			e.GET("/metrics-test/:date", test.args)
+			counter := httpRequests.WithLabelValues("/metrics-test/:date", http.MethodGet, test.wantStatus)
+			before := testutil.ToFloat64(counter)
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics-test/2022-02-01", nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
+
+func TestMetricsNotFound(t *testing.T) {
+	e := echo.New()
+	e.Use(Metrics)
+	e.GET("/metrics-test/:date", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	counter := httpRequests.WithLabelValues("", http.MethodGet, "404")
+	before := testutil.ToFloat64(counter)
+
+	req := httptest.NewRequest(http.MethodGet, "/unknown/path", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+	assert.Equal(t, 0.0, testutil.ToFloat64(httpRequests.WithLabelValues("/unknown/path", http.MethodGet, "404")))
+}
@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/labstack/echo"
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+)
+
+type scaleGraphQLHandler struct {
+	scaleUsecase domain.ScaleUsecase
+	schema       graphql.Schema
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+var (
+	scaleType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Scale",
+		Fields: graphql.Fields{
+			"date": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(domain.Scale).Date.Format(common.TimeLayout), nil
+				},
+			},
+			"min":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"max":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"difference": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"version":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	scaleAverrageType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ScaleAverrage",
+		Fields: graphql.Fields{
+			"min":        &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"max":        &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"difference": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+
+	scaleSummaryType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ScaleSummary",
+		Fields: graphql.Fields{
+			"count": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"first": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Date of the oldest reading",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return formatDate(p.Source.(*domain.ScaleSummary).First), nil
+				},
+			},
+			"last": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Date of the newest reading",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return formatDate(p.Source.(*domain.ScaleSummary).Last), nil
+				},
+			},
+			"lowest":  &graphql.Field{Type: graphql.Int, Description: "Lowest min"},
+			"highest": &graphql.Field{Type: graphql.Int, Description: "Highest max"},
+			"average": &graphql.Field{Type: scaleAverrageType},
+		},
+	})
+
+	rangeArgs = graphql.FieldConfigArgument{
+		"from": &graphql.ArgumentConfig{Type: graphql.String},
+		"to":   &graphql.ArgumentConfig{Type: graphql.String},
+	}
+
+	scaleArgs = graphql.FieldConfigArgument{
+		"date": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+		"min":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+		"max":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+	}
+)
+
+func NewScaleGraphQLHandler(e *echo.Echo, scaleUsecase domain.ScaleUsecase) {
+	handler := &scaleGraphQLHandler{
+		scaleUsecase: scaleUsecase,
+	}
+
+	schema, err := handler.newSchema()
+	if err != nil {
+		panic(err)
+	}
+	handler.schema = schema
+
+	e.GET("/graphql", handler.Query)
+	e.POST("/graphql", handler.Query, helper.BodyLimit(graphQLBodyLimit))
+}
+
+func (h *scaleGraphQLHandler) newSchema() (graphql.Schema, error) {
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"scales": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(scaleType))),
+				Description: "Readings newest first, from and to are inclusive 2006-01-02 dates",
+				Args:        rangeArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					from, to := rangeFromArgs(p.Args)
+					return h.scaleUsecase.GetScalesInRange(p.Context, from, to)
+				},
+			},
+			"scale": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(scaleType))),
+				Args: graphql.FieldConfigArgument{
+					"date": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return h.scaleUsecase.GetScale(p.Context, p.Args["date"].(string))
+				},
+			},
+			"average": &graphql.Field{
+				Type:        scaleAverrageType,
+				Description: "Average of the readings in range, null without any",
+				Args:        rangeArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					from, to := rangeFromArgs(p.Args)
+					summary, err := h.scaleUsecase.GetSummary(p.Context, from, to)
+					if err != nil {
+						return nil, err
+					}
+					return summary.Average, nil
+				},
+			},
+			"summary": &graphql.Field{
+				Type:        graphql.NewNonNull(scaleSummaryType),
+				Description: "Summary of the readings in range",
+				Args:        rangeArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					from, to := rangeFromArgs(p.Args)
+					return h.scaleUsecase.GetSummary(p.Context, from, to)
+				},
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createScale": &graphql.Field{
+				Type: graphql.NewNonNull(scaleType),
+				Args: scaleArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					scale, err := scaleFromArgs(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					err = h.scaleUsecase.Create(p.Context, scale)
+					if err != nil {
+						return nil, err
+					}
+					return *scale, nil
+				},
+			},
+			"updateScale": &graphql.Field{
+				Type:        graphql.NewNonNull(scaleType),
+				Description: "Fails unless version is current when given",
+				Args: graphql.FieldConfigArgument{
+					"date":    scaleArgs["date"],
+					"min":     scaleArgs["min"],
+					"max":     scaleArgs["max"],
+					"version": &graphql.ArgumentConfig{Type: graphql.Int},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					scale, err := scaleFromArgs(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					scale.Version, _ = p.Args["version"].(int)
+					err = h.scaleUsecase.Update(p.Context, scale)
+					if err != nil {
+						return nil, err
+					}
+					return *scale, nil
+				},
+			},
+			"deleteScale": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Boolean),
+				Description: "Fails unless version is current when given",
+				Args: graphql.FieldConfigArgument{
+					"date":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"version": &graphql.ArgumentConfig{Type: graphql.Int},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					version, _ := p.Args["version"].(int)
+					err := h.scaleUsecase.Delete(p.Context, p.Args["date"].(string), version)
+					return err == nil, err
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+func scaleFromArgs(args map[string]interface{}) (*domain.Scale, error) {
+	date, err := time.Parse(common.TimeLayout, args["date"].(string))
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Scale{
+		Date: date,
+		Min:  args["min"].(int),
+		Max:  args["max"].(int),
+	}, nil
+}
+
+// rangeFromArgs returns the from and to arguments, empty when left out.
+func rangeFromArgs(args map[string]interface{}) (string, string) {
+	from, _ := args["from"].(string)
+	to, _ := args["to"].(string)
+	return from, to
+}
+
+// formatDate keeps a missing date null rather than a typed nil.
+func formatDate(date *time.Time) interface{} {
+	if date == nil {
+		return nil
+	}
+	return date.Format(common.TimeLayout)
+}
+
+// operation returns the type of the operation req runs, empty when the query
+// does not parse or names none, graphql.Do then reports it.
+func operation(req *graphQLRequest) string {
+	document, err := parser.Parse(parser.ParseParams{Source: req.Query})
+	if err != nil {
+		return ""
+	}
+
+	var operations []*ast.OperationDefinition
+	for _, definition := range document.Definitions {
+		op, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if req.OperationName == "" || (op.Name != nil && op.Name.Value == req.OperationName) {
+			operations = append(operations, op)
+		}
+	}
+	if len(operations) != 1 {
+		return ""
+	}
+	return operations[0].Operation
+}
+
+func (h *scaleGraphQLHandler) Query(c echo.Context) error {
+	req := &graphQLRequest{}
+	if c.Request().Method == http.MethodGet {
+		req.Query = c.QueryParam("query")
+		req.OperationName = c.QueryParam("operationName")
+		if variables := c.QueryParam("variables"); variables != "" {
+			err := json.Unmarshal([]byte(variables), &req.Variables)
+			if err != nil {
+				code := http.StatusBadRequest
+				return c.JSON(code, helper.Response(code, "Failed execute query", nil, err.Error()))
+			}
+		}
+		// links and images can make a browser send a GET, writes need a POST
+		if op := operation(req); op != "" && op != ast.OperationTypeQuery {
+			c.Response().Header().Set(echo.HeaderAllow, http.MethodPost)
+			code := http.StatusMethodNotAllowed
+			return c.JSON(code, helper.Response(code, "Failed execute query", nil, op+" needs a POST"))
+		}
+	} else {
+		err := c.Bind(req)
+		if err != nil {
+			code := http.StatusBadRequest
+			return c.JSON(code, helper.Response(code, "Failed execute query", nil, err.Error()))
+		}
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        c.Request().Context(),
+	})
+	return c.JSON(http.StatusOK, result)
+}
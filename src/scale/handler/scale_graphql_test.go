@@ -0,0 +1,333 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQLQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+	scales := &domain.ScaleResponse{
+		Scales: []domain.Scale{
+			{
+				Date:       date.AddDate(0, 0, 2),
+				Min:        48,
+				Max:        50,
+				Difference: 2,
+			},
+			{
+				Date:       date.AddDate(0, 0, 1),
+				Min:        47,
+				Max:        51,
+				Difference: 4,
+			},
+			{
+				Date:       date,
+				Min:        47,
+				Max:        50,
+				Difference: 3,
+			},
+		},
+		Average: &domain.ScaleAverrage{
+			Min:        47.3,
+			Max:        50.3,
+			Difference: 3,
+		},
+	}
+
+	type args struct {
+		method    string
+		body      string
+		variables string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantCode   int
+		wantResult string
+		mock       func()
+	}{
+		{
+			name: "scales with range and average",
+			args: args{
+				method: http.MethodPost,
+				body:   `{"query":"query($from: String) { scales(from: $from, to: \"2022-02-02\") { date min max difference } average { min } }","variables":{"from":"2022-02-02"}}`,
+			},
+			wantCode: http.StatusOK,
+			wantResult: `{"data":{"average":{"min":47.3},"scales":[{"date":"2022-02-02","difference":4,"max":51,"min":47}]}}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScalesInRange(gomock.Any(), "2022-02-02", "2022-02-02").Return(scales.Scales[1:2], nil)
+				scaleMock.EXPECT().GetSummary(gomock.Any(), "", "").Return(&domain.ScaleSummary{Count: 3, Average: scales.Average}, nil)
+			},
+		},
+		{
+			name: "scale by get",
+			args: args{
+				method: http.MethodGet,
+				body:   `{ scale(date: "2022-02-01") { date min } }`,
+			},
+			wantCode: http.StatusOK,
+			wantResult: `{"data":{"scale":[{"date":"2022-02-01","min":47}]}}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return(scales.Scales[2:], nil)
+			},
+		},
+		{
+			name: "scale by get with variables",
+			args: args{
+				method:    http.MethodGet,
+				body:      `query($date: String!) { scale(date: $date) { date } }`,
+				variables: `{"date":"2022-02-01"}`,
+			},
+			wantCode: http.StatusOK,
+			wantResult: `{"data":{"scale":[{"date":"2022-02-01"}]}}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return(scales.Scales[2:], nil)
+			},
+		},
+		{
+			name: "invalid variables by get",
+			args: args{
+				method:    http.MethodGet,
+				body:      `query($date: String!) { scale(date: $date) { date } }`,
+				variables: `{"date":`,
+			},
+			wantCode:   http.StatusBadRequest,
+			wantResult: `{"code":400,"message":"Failed execute query"`,
+			mock:       func() {},
+		},
+		{
+			name: "mutation by get",
+			args: args{
+				method: http.MethodGet,
+				body:   `mutation { deleteScale(date: "2022-02-01") }`,
+			},
+			wantCode:   http.StatusMethodNotAllowed,
+			wantResult: `{"code":405,"message":"Failed execute query","data":null,"errors":"mutation needs a POST"}`,
+			mock:       func() {},
+		},
+		{
+			name: "average and summary with range",
+			args: args{
+				method: http.MethodPost,
+				body:   `{"query":"{ average(from: \"2022-02-02\") { min max difference } summary(to: \"2022-02-02\") { count first last lowest highest average { min } } }"}`,
+			},
+			wantCode: http.StatusOK,
+			wantResult: `{"data":{"average":{"difference":3,"max":50.5,"min":47.5},"summary":{"average":{"min":47},"count":2,"first":"2022-02-01","highest":51,"last":"2022-02-02","lowest":47}}}
+`,
+			mock: func() {
+				first, last := date, date.AddDate(0, 0, 1)
+				lowest, highest := 47, 51
+				scaleMock.EXPECT().GetSummary(gomock.Any(), "2022-02-02", "").Return(&domain.ScaleSummary{
+					Count:   2,
+					Average: &domain.ScaleAverrage{Min: 47.5, Max: 50.5, Difference: 3},
+				}, nil)
+				scaleMock.EXPECT().GetSummary(gomock.Any(), "", "2022-02-02").Return(&domain.ScaleSummary{
+					Count:   2,
+					First:   &first,
+					Last:    &last,
+					Lowest:  &lowest,
+					Highest: &highest,
+					Average: &domain.ScaleAverrage{Min: 47, Max: 50.5, Difference: 3.5},
+				}, nil)
+			},
+		},
+		{
+			name: "summary without readings",
+			args: args{
+				method: http.MethodPost,
+				body:   `{"query":"{ average(from: \"2022-03-01\") { min } summary(from: \"2022-03-01\") { count first average { min } } }"}`,
+			},
+			wantCode: http.StatusOK,
+			wantResult: `{"data":{"average":null,"summary":{"average":null,"count":0,"first":null}}}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetSummary(gomock.Any(), "2022-03-01", "").Return(&domain.ScaleSummary{}, nil).Times(2)
+			},
+		},
+		{
+			name: "invalid range",
+			args: args{
+				method: http.MethodPost,
+				body:   `{"query":"{ scales(from: \"date\") { date } }"}`,
+			},
+			wantCode:   http.StatusOK,
+			wantResult: `"message":"parsing time \"date\" as \"2006-01-02\": cannot parse \"date\" as \"2006\""`,
+			mock: func() {
+				scaleMock.EXPECT().GetScalesInRange(gomock.Any(), "date", "").Return(nil, &time.ParseError{Layout: common.TimeLayout, Value: "date", LayoutElem: "2006", ValueElem: "date"})
+			},
+		},
+		{
+			name: "usecase error",
+			args: args{
+				method: http.MethodPost,
+				body:   `{"query":"{ average { min } }"}`,
+			},
+			wantCode:   http.StatusOK,
+			wantResult: `"message":"some error"`,
+			mock: func() {
+				scaleMock.EXPECT().GetSummary(gomock.Any(), "", "").Return(nil, errors.New("some error"))
+			},
+		},
+		{
+			name: "error body",
+			args: args{
+				method: http.MethodPost,
+				body:   `{"query":`,
+			},
+			wantCode:   http.StatusBadRequest,
+			wantResult: `{"code":400,"message":"Failed execute query"`,
+			mock:       func() {},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			target := "/graphql"
+			var body *strings.Reader
+			if test.args.method == http.MethodGet {
+				target += "?query=" + url.QueryEscape(test.args.body)
+				if test.args.variables != "" {
+					target += "&variables=" + url.QueryEscape(test.args.variables)
+				}
+				body = strings.NewReader("")
+			} else {
+				body = strings.NewReader(test.args.body)
+			}
+			req := httptest.NewRequest(test.args.method, target, body)
+			req.Header.Set("content-type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			h := &scaleGraphQLHandler{
+				scaleUsecase: scaleMock,
+			}
+			schema, err := h.newSchema()
+			if !assert.NoError(t, err) {
+				return
+			}
+			h.schema = schema
+
+			test.mock()
+
+			if assert.NoError(t, h.Query(c)) {
+				assert.Equal(t, test.wantCode, rec.Code)
+				assert.Contains(t, rec.Body.String(), test.wantResult)
+			}
+		})
+	}
+}
+
+func TestGraphQLMutation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date, _ := time.Parse(common.TimeLayout, "2022-02-01")
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+
+	tests := []struct {
+		name       string
+		args       string
+		wantResult string
+		mock       func()
+	}{
+		{
+			name: "create",
+			args: `{"query":"mutation { createScale(date: \"2022-02-01\", min: 45, max: 50) { date difference } }"}`,
+			wantResult: `{"data":{"createScale":{"date":"2022-02-01","difference":5}}}
+`,
+			mock: func() {
+				scaleMock.EXPECT().Create(gomock.Any(), &domain.Scale{
+					Date: date,
+					Min:  45,
+					Max:  50,
+				}).DoAndReturn(func(ctx context.Context, param *domain.Scale) error {
+					param.Difference = param.Max - param.Min
+					return nil
+				})
+			},
+		},
+		{
+			name:       "create error",
+			args:       `{"query":"mutation { createScale(date: \"2022-02-01\", min: 50, max: 45) { date } }"}`,
+			wantResult: `"message":"Min. greater than max."`,
+			mock: func() {
+				scaleMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.ErrMinAboveMax)
+			},
+		},
+		{
+			name: "update",
+			args: `{"query":"mutation { updateScale(date: \"2022-02-01\", min: 46, max: 50) { min max } }"}`,
+			wantResult: `{"data":{"updateScale":{"max":50,"min":46}}}
+`,
+			mock: func() {
+				scaleMock.EXPECT().Update(gomock.Any(), &domain.Scale{
+					Date: date,
+					Min:  46,
+					Max:  50,
+				}).Return(nil)
+			},
+		},
+		{
+			name: "delete",
+			args: `{"query":"mutation { deleteScale(date: \"2022-02-01\") }"}`,
+			wantResult: `{"data":{"deleteScale":true}}
+`,
+			mock: func() {
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", 0).Return(nil)
+			},
+		},
+		{
+			name: "delete version",
+			args: `{"query":"mutation { deleteScale(date: \"2022-02-01\", version: 3) }"}`,
+			wantResult: `{"data":{"deleteScale":true}}
+`,
+			mock: func() {
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", 3).Return(nil)
+			},
+		},
+		{
+			name:       "delete error",
+			args:       `{"query":"mutation { deleteScale(date: \"2022-02-01\") }"}`,
+			wantResult: `"message":"your requested item is not found"`,
+			mock: func() {
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", 0).Return(domain.ErrNotFound)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			NewScaleGraphQLHandler(e, scaleMock)
+			req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(test.args))
+			req.Header.Set("content-type", "application/json")
+			rec := httptest.NewRecorder()
+
+			test.mock()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), test.wantResult)
+		})
+	}
+}
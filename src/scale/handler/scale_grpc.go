@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/scale/src/proto/scalepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type scaleGRPCHandler struct {
+	scalepb.UnimplementedScaleServiceServer
+	scaleUsecase domain.ScaleUsecase
+}
+
+func NewScaleGRPCHandler(s *grpc.Server, scaleUsecase domain.ScaleUsecase) {
+	handler := &scaleGRPCHandler{
+		scaleUsecase: scaleUsecase,
+	}
+
+	scalepb.RegisterScaleServiceServer(s, handler)
+}
+
+func toScalePB(scale domain.Scale) *scalepb.Scale {
+	return &scalepb.Scale{
+		Date:       scale.Date.Format(common.TimeLayout),
+		Min:        int32(scale.Min),
+		Max:        int32(scale.Max),
+		Difference: int32(scale.Difference),
+		Version:    int32(scale.Version),
+	}
+}
+
+func toScalesPB(scales []domain.Scale) []*scalepb.Scale {
+	result := make([]*scalepb.Scale, len(scales))
+	for i, scale := range scales {
+		result[i] = toScalePB(scale)
+	}
+	return result
+}
+
+func (h *scaleGRPCHandler) Create(ctx context.Context, req *scalepb.CreateRequest) (*scalepb.CreateResponse, error) {
+	date, err := time.Parse(common.TimeLayout, req.GetDate())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = h.scaleUsecase.Create(ctx, &domain.Scale{
+		Date: date,
+		Min:  int(req.GetMin()),
+		Max:  int(req.GetMax()),
+	})
+	if err != nil {
+		return nil, status.Error(helper.GetGRPCCode(err), err.Error())
+	}
+
+	return &scalepb.CreateResponse{}, nil
+}
+
+func (h *scaleGRPCHandler) GetScales(ctx context.Context, req *scalepb.GetScalesRequest) (*scalepb.GetScalesResponse, error) {
+	scales, err := h.scaleUsecase.GetScales(ctx)
+	if err != nil {
+		return nil, status.Error(helper.GetGRPCCode(err), err.Error())
+	}
+
+	res := &scalepb.GetScalesResponse{
+		Scales: toScalesPB(scales.Scales),
+	}
+	if scales.Average != nil {
+		res.Average = &scalepb.ScaleAverage{
+			Min:        scales.Average.Min,
+			Max:        scales.Average.Max,
+			Difference: scales.Average.Difference,
+		}
+	}
+	return res, nil
+}
+
+func (h *scaleGRPCHandler) GetScale(ctx context.Context, req *scalepb.GetScaleRequest) (*scalepb.GetScaleResponse, error) {
+	_, err := time.Parse(common.TimeLayout, req.GetDate())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	scales, err := h.scaleUsecase.GetScale(ctx, req.GetDate())
+	if err != nil {
+		return nil, status.Error(helper.GetGRPCCode(err), err.Error())
+	}
+
+	return &scalepb.GetScaleResponse{Scales: toScalesPB(scales)}, nil
+}
+
+func (h *scaleGRPCHandler) Update(ctx context.Context, req *scalepb.UpdateRequest) (*scalepb.UpdateResponse, error) {
+	date, err := time.Parse(common.TimeLayout, req.GetDate())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	scale := &domain.Scale{
+		Date:    date,
+		Min:     int(req.GetMin()),
+		Max:     int(req.GetMax()),
+		Version: int(req.GetVersion()),
+	}
+	err = h.scaleUsecase.Update(ctx, scale)
+	if err != nil {
+		return nil, status.Error(helper.GetGRPCCode(err), err.Error())
+	}
+
+	return &scalepb.UpdateResponse{Version: int32(scale.Version)}, nil
+}
+
+func (h *scaleGRPCHandler) Delete(ctx context.Context, req *scalepb.DeleteRequest) (*scalepb.DeleteResponse, error) {
+	_, err := time.Parse(common.TimeLayout, req.GetDate())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = h.scaleUsecase.Delete(ctx, req.GetDate(), int(req.GetVersion()))
+	if err != nil {
+		return nil, status.Error(helper.GetGRPCCode(err), err.Error())
+	}
+
+	return &scalepb.DeleteResponse{}, nil
+}
+
+func (h *scaleGRPCHandler) ListScales(req *scalepb.ListScalesRequest, stream scalepb.ScaleService_ListScalesServer) error {
+	scales, err := h.scaleUsecase.GetScales(stream.Context())
+	if err != nil {
+		return status.Error(helper.GetGRPCCode(err), err.Error())
+	}
+
+	for _, scale := range scales.Scales {
+		err = stream.Send(toScalePB(scale))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
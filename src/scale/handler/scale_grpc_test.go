@@ -0,0 +1,409 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/scale/src/proto/scalepb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+func newGRPCClient(t *testing.T, scaleUsecase domain.ScaleUsecase, opts ...grpc.ServerOption) scalepb.ScaleServiceClient {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(opts...)
+	NewScaleGRPCHandler(s, scaleUsecase)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return scalepb.NewScaleServiceClient(conn)
+}
+
+func TestGRPCCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date, _ := time.Parse(common.TimeLayout, "2022-02-01")
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+	client := newGRPCClient(t, scaleMock)
+
+	tests := []struct {
+		name     string
+		args     *scalepb.CreateRequest
+		wantCode codes.Code
+		mock     func()
+	}{
+		{
+			name:     "success",
+			args:     &scalepb.CreateRequest{Date: "2022-02-01", Min: 45, Max: 50},
+			wantCode: codes.OK,
+			mock: func() {
+				scaleMock.EXPECT().Create(gomock.Any(), &domain.Scale{
+					Date: date,
+					Min:  45,
+					Max:  50,
+				}).Return(nil)
+			},
+		},
+		{
+			name:     "error param",
+			args:     &scalepb.CreateRequest{Date: "date", Min: 45, Max: 50},
+			wantCode: codes.InvalidArgument,
+			mock:     func() {},
+		},
+		{
+			name:     "error",
+			args:     &scalepb.CreateRequest{Date: "2022-02-01", Min: 45, Max: 50},
+			wantCode: codes.Internal,
+			mock: func() {
+				scaleMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+
+			_, err := client.Create(context.Background(), test.args)
+			assert.Equal(t, test.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestGRPCGetScales(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+	client := newGRPCClient(t, scaleMock)
+
+	tests := []struct {
+		name       string
+		wantResult *scalepb.GetScalesResponse
+		wantCode   codes.Code
+		mock       func()
+	}{
+		{
+			name: "success",
+			wantResult: &scalepb.GetScalesResponse{
+				Scales: []*scalepb.Scale{
+					{Date: "2022-02-01", Min: 47, Max: 50, Difference: 3},
+				},
+				Average: &scalepb.ScaleAverage{Min: 47, Max: 50, Difference: 3},
+			},
+			wantCode: codes.OK,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(&domain.ScaleResponse{
+					Scales: []domain.Scale{
+						{
+							Date:       date,
+							Min:        47,
+							Max:        50,
+							Difference: 3,
+						},
+					},
+					Average: &domain.ScaleAverrage{
+						Min:        47,
+						Max:        50,
+						Difference: 3,
+					},
+				}, nil)
+			},
+		},
+		{
+			name:     "error",
+			wantCode: codes.Internal,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+
+			got, err := client.GetScales(context.Background(), &scalepb.GetScalesRequest{})
+			assert.Equal(t, test.wantCode, status.Code(err))
+			if test.wantResult != nil {
+				assert.True(t, proto.Equal(test.wantResult, got), "got %v", got)
+			}
+		})
+	}
+}
+
+func TestGRPCGetScale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+	client := newGRPCClient(t, scaleMock)
+
+	tests := []struct {
+		name       string
+		args       string
+		wantResult *scalepb.GetScaleResponse
+		wantCode   codes.Code
+		mock       func()
+	}{
+		{
+			name: "success",
+			args: "2022-02-01",
+			wantResult: &scalepb.GetScaleResponse{
+				Scales: []*scalepb.Scale{
+					{Date: "2022-02-01", Min: 47, Max: 50, Difference: 3},
+				},
+			},
+			wantCode: codes.OK,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{
+					{
+						Date:       date,
+						Min:        47,
+						Max:        50,
+						Difference: 3,
+					},
+				}, nil)
+			},
+		},
+		{
+			name:     "error param",
+			args:     "date",
+			wantCode: codes.InvalidArgument,
+			mock:     func() {},
+		},
+		{
+			name:     "not found",
+			args:     "2022-02-01",
+			wantCode: codes.NotFound,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return(nil, domain.ErrNotFound)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+
+			got, err := client.GetScale(context.Background(), &scalepb.GetScaleRequest{Date: test.args})
+			assert.Equal(t, test.wantCode, status.Code(err))
+			if test.wantResult != nil {
+				assert.True(t, proto.Equal(test.wantResult, got), "got %v", got)
+			}
+		})
+	}
+}
+
+func TestGRPCUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date, _ := time.Parse(common.TimeLayout, "2022-02-01")
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+	client := newGRPCClient(t, scaleMock)
+
+	tests := []struct {
+		name     string
+		args     *scalepb.UpdateRequest
+		wantCode codes.Code
+		mock     func()
+	}{
+		{
+			name:     "success",
+			args:     &scalepb.UpdateRequest{Date: "2022-02-01", Min: 45, Max: 50},
+			wantCode: codes.OK,
+			mock: func() {
+				scaleMock.EXPECT().Update(gomock.Any(), &domain.Scale{
+					Date: date,
+					Min:  45,
+					Max:  50,
+				}).Return(nil)
+			},
+		},
+		{
+			name:     "error param",
+			args:     &scalepb.UpdateRequest{Date: "date", Min: 45, Max: 50},
+			wantCode: codes.InvalidArgument,
+			mock:     func() {},
+		},
+		{
+			name:     "stale version",
+			args:     &scalepb.UpdateRequest{Date: "2022-02-01", Min: 45, Max: 50, Version: 2},
+			wantCode: codes.Aborted,
+			mock: func() {
+				scaleMock.EXPECT().Update(gomock.Any(), &domain.Scale{
+					Date:    date,
+					Min:     45,
+					Max:     50,
+					Version: 2,
+				}).Return(domain.ErrPreconditionFailed)
+			},
+		},
+		{
+			name:     "error",
+			args:     &scalepb.UpdateRequest{Date: "2022-02-01", Min: 45, Max: 50},
+			wantCode: codes.Internal,
+			mock: func() {
+				scaleMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+
+			_, err := client.Update(context.Background(), test.args)
+			assert.Equal(t, test.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestGRPCDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+	client := newGRPCClient(t, scaleMock)
+
+	tests := []struct {
+		name     string
+		args     string
+		wantCode codes.Code
+		mock     func()
+	}{
+		{
+			name:     "success",
+			args:     "2022-02-01",
+			wantCode: codes.OK,
+			mock: func() {
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", 0).Return(nil)
+			},
+		},
+		{
+			name:     "error param",
+			args:     "date",
+			wantCode: codes.InvalidArgument,
+			mock:     func() {},
+		},
+		{
+			name:     "error",
+			args:     "2022-02-01",
+			wantCode: codes.Internal,
+			mock: func() {
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", 0).Return(errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+
+			_, err := client.Delete(context.Background(), &scalepb.DeleteRequest{Date: test.args})
+			assert.Equal(t, test.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestGRPCListScales(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+	client := newGRPCClient(t, scaleMock)
+
+	tests := []struct {
+		name       string
+		wantResult []*scalepb.Scale
+		wantCode   codes.Code
+		mock       func()
+	}{
+		{
+			name: "success",
+			wantResult: []*scalepb.Scale{
+				{Date: "2022-02-02", Min: 48, Max: 50, Difference: 2},
+				{Date: "2022-02-01", Min: 47, Max: 50, Difference: 3},
+			},
+			wantCode: codes.OK,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(&domain.ScaleResponse{
+					Scales: []domain.Scale{
+						{
+							Date:       date.AddDate(0, 0, 1),
+							Min:        48,
+							Max:        50,
+							Difference: 2,
+						},
+						{
+							Date:       date,
+							Min:        47,
+							Max:        50,
+							Difference: 3,
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:     "error",
+			wantCode: codes.Internal,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+
+			stream, err := client.ListScales(context.Background(), &scalepb.ListScalesRequest{})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got := []*scalepb.Scale{}
+			for {
+				scale, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					assert.Equal(t, test.wantCode, status.Code(err))
+					return
+				}
+				got = append(got, scale)
+			}
+			assert.Equal(t, test.wantCode, codes.OK)
+			assert.Equal(t, len(test.wantResult), len(got))
+			for i := range got {
+				assert.True(t, proto.Equal(test.wantResult[i], got[i]), "got %v", got[i])
+			}
+		})
+	}
+}
@@ -1,12 +1,19 @@
 package handler
 
 import (
+	"fmt"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/scale/src/chart"
 	"github.com/scale/src/common"
 	"github.com/scale/src/domain"
 	"github.com/scale/src/helper"
+	"github.com/scale/src/report"
 
 	"github.com/labstack/echo"
 )
@@ -20,11 +27,68 @@ func NewScaleHandler(e *echo.Echo, scaleUsecase domain.ScaleUsecase) {
 		scaleUsecase: scaleUsecase,
 	}
 
-	e.POST("/scale", handler.Create)
-	e.GET("/scale", handler.GetScale)
-	e.GET("/scales", handler.GetScales)
-	e.DELETE("/scale", handler.DeleteScale)
-	e.PATCH("/scale", handler.Update)
+	// unversioned routes are kept as aliases of v1 for existing clients
+	handler.registerV1(e)
+	handler.registerV1(e.Group("/v1"))
+	handler.registerV2(e.Group("/v2"))
+}
+
+type router interface {
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// etag is the version of the readings of a date, they are written together.
+func etag(scales ...domain.Scale) string {
+	version := 0
+	for _, scale := range scales {
+		if scale.Version > version {
+			version = scale.Version
+		}
+	}
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+func setETag(c echo.Context, scales ...domain.Scale) {
+	if len(scales) > 0 {
+		c.Response().Header().Set(common.HeaderETag, etag(scales...))
+	}
+}
+
+// ifMatch returns the version a write is conditional on, 0 when unconditional.
+// Weak and malformed tags never match.
+func ifMatch(c echo.Context) int {
+	tag := strings.TrimSpace(c.Request().Header.Get(common.HeaderIfMatch))
+	if tag == "" || tag == "*" {
+		return 0
+	}
+
+	value, err := strconv.Unquote(tag)
+	if err != nil {
+		return -1
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil || version <= 0 {
+		return -1
+	}
+	return version
+}
+
+func (h *scaleHandler) registerV1(r router) {
+	r.POST("/scale", h.Create, helper.BodyLimit(scaleBodyLimit))
+	r.GET("/scale", h.GetScale)
+	r.GET("/scales", h.GetScales)
+	r.GET("/scales/chart.svg", h.GetChart)
+	r.GET("/scales/report.pdf", h.GetReport)
+	r.GET("/scales/:date/history", h.GetHistory)
+	r.GET("/scales/trash", h.GetTrash)
+	r.POST("/scales/:date/restore", h.Restore)
+	r.POST("/scales/batch", h.Batch, helper.BodyLimit(DefaultBodyLimit))
+	r.DELETE("/scale", h.DeleteScale)
+	r.PATCH("/scale", h.Update, helper.BodyLimit(scaleBodyLimit))
 }
 
 func (h *scaleHandler) Create(c echo.Context) error {
@@ -46,22 +110,24 @@ func (h *scaleHandler) Create(c echo.Context) error {
 		return c.JSON(code, helper.Response(code, "Failed create scale", nil, err.Error()))
 	}
 
-	err = h.scaleUsecase.Create(&domain.Scale{
+	scale := &domain.Scale{
 		Date: date,
 		Min:  payload.Min,
 		Max:  payload.Max,
-	})
+	}
+	err = h.scaleUsecase.Create(c.Request().Context(), scale)
 	if err != nil {
 		code := helper.GetStatusCode(err)
 		return c.JSON(code, helper.Response(code, "Failed create scale", nil, err.Error()))
 	}
 
+	setETag(c, *scale)
 	data := helper.Response(200, "Success create scale", nil, nil)
 	return c.JSON(http.StatusOK, data)
 }
 
 func (h *scaleHandler) GetScales(c echo.Context) error {
-	scales, err := h.scaleUsecase.GetScales()
+	scales, err := h.scaleUsecase.GetScales(c.Request().Context())
 	if err != nil {
 		code := helper.GetStatusCode(err)
 		return c.JSON(code, helper.Response(code, "Failed get scales", nil, err.Error()))
@@ -71,25 +137,189 @@ func (h *scaleHandler) GetScales(c echo.Context) error {
 	return c.JSON(http.StatusOK, data)
 }
 
+func (h *scaleHandler) GetChart(c echo.Context) error {
+	opt, err := chartOptions(c.Request().URL.Query())
+	if err != nil {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed get chart", nil, err.Error()))
+	}
+
+	scales, err := h.scaleUsecase.GetScales(c.Request().Context())
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed get chart", nil, err.Error()))
+	}
+
+	return c.Blob(http.StatusOK, "image/svg+xml", chart.SVG(scales.Scales, opt))
+}
+
+func chartOptions(query url.Values) (chart.Options, error) {
+	opt := chart.Options{}
+	var err error
+
+	if from := query.Get("from"); from != "" {
+		opt.From, err = time.Parse(common.TimeLayout, from)
+		if err != nil {
+			return opt, err
+		}
+	}
+	if to := query.Get("to"); to != "" {
+		opt.To, err = time.Parse(common.TimeLayout, to)
+		if err != nil {
+			return opt, err
+		}
+	}
+	if window := query.Get("moving_average"); window != "" {
+		opt.MovingAverage, err = strconv.Atoi(window)
+		if err != nil || opt.MovingAverage < 0 {
+			return opt, domain.ErrBadParamInput
+		}
+	}
+	if goal := query.Get("goal"); goal != "" {
+		value, err := strconv.ParseFloat(goal, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return opt, domain.ErrBadParamInput
+		}
+		opt.Goal = &value
+	}
+
+	return opt, nil
+}
+
+func (h *scaleHandler) GetReport(c echo.Context) error {
+	query := c.Request().URL.Query()
+	month, err := time.ParseInLocation(common.MonthLayout, query.Get("month"), helper.GetLocation())
+	if err != nil {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed get report", nil, err.Error()))
+	}
+
+	first, last := report.MonthRange(month)
+	from, to := first.Format(common.TimeLayout), last.Format(common.TimeLayout)
+	scales, err := h.scaleUsecase.GetScalesInRange(c.Request().Context(), from, to)
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed get report", nil, err.Error()))
+	}
+	summary, err := h.scaleUsecase.GetSummary(c.Request().Context(), from, to)
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed get report", nil, err.Error()))
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`inline; filename="report-%s.pdf"`, month.Format(common.MonthLayout)))
+	return c.Blob(http.StatusOK, "application/pdf", report.PDF(&report.Report{
+		User:    query.Get("user"),
+		Month:   month,
+		Scales:  scales,
+		Average: summary.Average,
+	}))
+}
+
 func (h *scaleHandler) GetScale(c echo.Context) error {
 	query := c.Request().URL.Query()
 	date := query.Get("date")
 	_, err := time.Parse(common.TimeLayout, date)
 	if err != nil {
-		code := helper.GetStatusCode(err)
-		return c.JSON(code, helper.Response(code, "Failed delete scale", nil, err.Error()))
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed get scale", nil, err.Error()))
 	}
 
-	scales, err := h.scaleUsecase.GetScale(date)
+	scales, err := h.scaleUsecase.GetScale(c.Request().Context(), date)
 	if err != nil {
 		code := helper.GetStatusCode(err)
 		return c.JSON(code, helper.Response(code, "Failed get scale", nil, err.Error()))
 	}
 
+	setETag(c, scales...)
 	data := helper.Response(200, "Success get scale", scales, nil)
 	return c.JSON(http.StatusOK, data)
 }
 
+func (h *scaleHandler) GetHistory(c echo.Context) error {
+	date := c.Param("date")
+	_, err := time.Parse(common.TimeLayout, date)
+	if err != nil {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed get history", nil, err.Error()))
+	}
+
+	history, err := h.scaleUsecase.GetHistory(c.Request().Context(), date)
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed get history", nil, err.Error()))
+	}
+
+	data := helper.Response(200, "Success get history", history, nil)
+	return c.JSON(http.StatusOK, data)
+}
+
+func (h *scaleHandler) GetTrash(c echo.Context) error {
+	scales, err := h.scaleUsecase.GetTrash(c.Request().Context())
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed get trash", nil, err.Error()))
+	}
+
+	data := helper.Response(200, "Success get trash", scales, nil)
+	return c.JSON(http.StatusOK, data)
+}
+
+func (h *scaleHandler) Restore(c echo.Context) error {
+	date := c.Param("date")
+	_, err := time.Parse(common.TimeLayout, date)
+	if err != nil {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed restore scale", nil, err.Error()))
+	}
+
+	err = h.scaleUsecase.Restore(c.Request().Context(), date)
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed restore scale", nil, err.Error()))
+	}
+
+	data := helper.Response(200, "Success restore scale", nil, nil)
+	return c.JSON(http.StatusOK, data)
+}
+
+func (h *scaleHandler) Batch(c echo.Context) error {
+	c.Echo().Validator = helper.NewValidator()
+	payload := &domain.ScaleBatchParam{}
+	err := c.Bind(payload)
+	if err != nil {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed batch scales", nil, err.Error()))
+	}
+	err = c.Validate(payload)
+	if err != nil {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed batch scales", nil, err.Error()))
+	}
+
+	results, err := h.scaleUsecase.Batch(c.Request().Context(), payload.Operations)
+	for i := range results {
+		switch {
+		case results[i].Error != "":
+			results[i].Status = helper.GetStatusCode(err)
+		case err != nil:
+			// rolled back with the failed operation
+			results[i].Status = http.StatusFailedDependency
+		case results[i].Op == domain.ScaleOperationCreate:
+			results[i].Status = http.StatusCreated
+		default:
+			results[i].Status = http.StatusOK
+		}
+	}
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed batch scales", results, err.Error()))
+	}
+
+	data := helper.Response(200, "Success batch scales", results, nil)
+	return c.JSON(http.StatusOK, data)
+}
+
 func (h *scaleHandler) Update(c echo.Context) error {
 	c.Echo().Validator = helper.NewValidator()
 	payload := &domain.ScaleParam{}
@@ -109,16 +339,21 @@ func (h *scaleHandler) Update(c echo.Context) error {
 		return c.JSON(code, helper.Response(code, "Failed update scale", nil, err.Error()))
 	}
 
-	err = h.scaleUsecase.Update(&domain.Scale{
-		Date: date,
-		Min:  payload.Min,
-		Max:  payload.Max,
-	})
+	scale := &domain.Scale{
+		Date:    date,
+		Min:     payload.Min,
+		Max:     payload.Max,
+		Version: ifMatch(c),
+	}
+	err = h.scaleUsecase.Update(c.Request().Context(), scale)
 	if err != nil {
 		code := helper.GetStatusCode(err)
 		return c.JSON(code, helper.Response(code, "Failed update scale", nil, err.Error()))
 	}
 
+	if scale.Version > 0 {
+		setETag(c, *scale)
+	}
 	data := helper.Response(200, "Success update scale", nil, nil)
 	return c.JSON(http.StatusOK, data)
 }
@@ -128,11 +363,11 @@ func (h *scaleHandler) DeleteScale(c echo.Context) error {
 	date := query.Get("date")
 	_, err := time.Parse(common.TimeLayout, date)
 	if err != nil {
-		code := helper.GetStatusCode(err)
+		code := http.StatusBadRequest
 		return c.JSON(code, helper.Response(code, "Failed delete scales", nil, err.Error()))
 	}
 
-	err = h.scaleUsecase.Delete(date)
+	err = h.scaleUsecase.Delete(c.Request().Context(), date, ifMatch(c))
 	if err != nil {
 		code := helper.GetStatusCode(err)
 		return c.JSON(code, helper.Response(code, "Failed delete scales", nil, err.Error()))
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -42,7 +43,7 @@ func TestCreate(t *testing.T) {
 `,
 			mock: func() {
 				date, _ = time.Parse(common.TimeLayout, "2022-02-01")
-				scaleMock.EXPECT().Create(&domain.Scale{
+				scaleMock.EXPECT().Create(gomock.Any(), &domain.Scale{
 					Date: date,
 					Min:  45,
 					Max:  50,
@@ -56,7 +57,7 @@ func TestCreate(t *testing.T) {
 			args: `{"date":"2022-02-01","min":45,"max":50}`,
 			mock: func() {
 				date, _ = time.Parse(common.TimeLayout, "2022-02-01")
-				scaleMock.EXPECT().Create(&domain.Scale{
+				scaleMock.EXPECT().Create(gomock.Any(), &domain.Scale{
 					Date: date,
 					Min:  45,
 					Max:  50,
@@ -98,10 +99,10 @@ func TestGetScales(t *testing.T) {
 	}{
 		{
 			name: "success",
-			wantResult: `{"code":200,"message":"Success get scales","data":{"scales":[{"date":"2022-02-01T00:00:00+07:00","min":47,"max":50,"difference":3},{"date":"2022-02-01T00:00:00+07:00","min":50,"max":53,"difference":3}],"average":{"min":48.5,"max":51.5,"difference":3}},"errors":null}
+			wantResult: `{"code":200,"message":"Success get scales","data":{"scales":[{"date":"2022-02-01T00:00:00+07:00","min":47,"max":50,"difference":3,"version":0},{"date":"2022-02-01T00:00:00+07:00","min":50,"max":53,"difference":3,"version":0}],"average":{"min":48.5,"max":51.5,"difference":3}},"errors":null}
 `,
 			mock: func() {
-				scaleMock.EXPECT().GetScales().Return(&domain.ScaleResponse{
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(&domain.ScaleResponse{
 					Scales: []domain.Scale{
 						{
 							Date:       date,
@@ -129,7 +130,7 @@ func TestGetScales(t *testing.T) {
 			wantResult: `{"code":500,"message":"Failed get scales","data":null,"errors":"some error"}
 `,
 			mock: func() {
-				scaleMock.EXPECT().GetScales().Return(nil, errors.New("some error"))
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(nil, errors.New("some error"))
 			},
 		},
 	}
@@ -169,10 +170,10 @@ func TestGetScale(t *testing.T) {
 		{
 			name: "success",
 			args: `?date=2022-02-01`,
-			wantResult: `{"code":200,"message":"Success get scale","data":[{"date":"2022-02-01T00:00:00+07:00","min":47,"max":50,"difference":3}],"errors":null}
+			wantResult: `{"code":200,"message":"Success get scale","data":[{"date":"2022-02-01T00:00:00+07:00","min":47,"max":50,"difference":3,"version":0}],"errors":null}
 `,
 			mock: func() {
-				scaleMock.EXPECT().GetScale("2022-02-01").Return([]domain.Scale{
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{
 					{
 						Date:       date,
 						Min:        47,
@@ -188,9 +189,16 @@ func TestGetScale(t *testing.T) {
 			wantResult: `{"code":500,"message":"Failed get scale","data":null,"errors":"some error"}
 `,
 			mock: func() {
-				scaleMock.EXPECT().GetScale("2022-02-01").Return(nil, errors.New("some error"))
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return(nil, errors.New("some error"))
 			},
 		},
+		{
+			name: "error param",
+			args: `?date=date`,
+			wantResult: `{"code":400,"message":"Failed get scale","data":null,"errors":"parsing time \"date\" as \"2006-01-02\": cannot parse \"date\" as \"2006\""}
+`,
+			mock: func() {},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -212,6 +220,266 @@ func TestGetScale(t *testing.T) {
 	}
 }
 
+func TestGetHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	changedAt := time.Date(2022, 2, 1, 7, 0, 0, 0, helper.GetLocation())
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+
+	tests := []struct {
+		name       string
+		args       string
+		wantResult string
+		mock       func()
+	}{
+		{
+			name: "success",
+			args: "/scales/2022-02-01/history",
+			wantResult: `{"code":200,"message":"Success get history","data":[{"date":"2022-02-01T00:00:00+07:00","action":"scale.updated","actor":"","before":[{"date":"2022-02-01T00:00:00+07:00","min":45,"max":50,"difference":5,"version":0}],"after":[{"date":"2022-02-01T00:00:00+07:00","min":46,"max":50,"difference":4,"version":0}],"changed_at":"2022-02-01T07:00:00+07:00"}],"errors":null}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetHistory(gomock.Any(), "2022-02-01").Return([]domain.ScaleHistory{
+					{
+						Date:      date,
+						Action:    domain.ScaleUpdated,
+						Before:    []domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5}},
+						After:     []domain.Scale{{Date: date, Min: 46, Max: 50, Difference: 4}},
+						ChangedAt: changedAt,
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "success v2",
+			args: "/v2/scales/2022-02-01/history",
+			wantResult: `{"code":200,"message":"Success get history","data":[],"errors":null}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetHistory(gomock.Any(), "2022-02-01").Return([]domain.ScaleHistory{}, nil)
+			},
+		},
+		{
+			name: "error param",
+			args: "/scales/date/history",
+			wantResult: `{"code":400,"message":"Failed get history","data":null,"errors":"parsing time \"date\" as \"2006-01-02\": cannot parse \"date\" as \"2006\""}
+`,
+			mock: func() {},
+		},
+		{
+			name: "error",
+			args: "/v1/scales/2022-02-01/history",
+			wantResult: `{"code":500,"message":"Failed get history","data":null,"errors":"some error"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetHistory(gomock.Any(), "2022-02-01").Return(nil, errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			NewScaleHandler(e, scaleMock)
+			req := httptest.NewRequest(http.MethodGet, test.args, nil)
+			rec := httptest.NewRecorder()
+
+			test.mock()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.wantResult, rec.Body.String())
+		})
+	}
+}
+
+func TestGetTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	deletedAt := time.Date(2022, 2, 2, 7, 0, 0, 0, helper.GetLocation())
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+
+	tests := []struct {
+		name       string
+		args       string
+		wantResult string
+		mock       func()
+	}{
+		{
+			name: "success",
+			args: "/scales/trash",
+			wantResult: `{"code":200,"message":"Success get trash","data":[{"date":"2022-02-01T00:00:00+07:00","min":45,"max":50,"difference":5,"version":0,"deleted_at":"2022-02-02T07:00:00+07:00"}],"errors":null}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetTrash(gomock.Any()).Return([]domain.Scale{
+					{
+						Date:       date,
+						Min:        45,
+						Max:        50,
+						Difference: 5,
+						DeletedAt:  &deletedAt,
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "error",
+			args: "/v2/scales/trash",
+			wantResult: `{"code":500,"message":"Failed get trash","data":null,"errors":"some error"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetTrash(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			NewScaleHandler(e, scaleMock)
+			req := httptest.NewRequest(http.MethodGet, test.args, nil)
+			rec := httptest.NewRecorder()
+
+			test.mock()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.wantResult, rec.Body.String())
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+
+	tests := []struct {
+		name       string
+		args       string
+		wantResult string
+		mock       func()
+	}{
+		{
+			name: "success",
+			args: "/scales/2022-02-01/restore",
+			wantResult: `{"code":200,"message":"Success restore scale","data":null,"errors":null}
+`,
+			mock: func() {
+				scaleMock.EXPECT().Restore(gomock.Any(), "2022-02-01").Return(nil)
+			},
+		},
+		{
+			name: "error param",
+			args: "/v1/scales/date/restore",
+			wantResult: `{"code":400,"message":"Failed restore scale","data":null,"errors":"parsing time \"date\" as \"2006-01-02\": cannot parse \"date\" as \"2006\""}
+`,
+			mock: func() {},
+		},
+		{
+			name: "not found",
+			args: "/v2/scales/2022-02-01/restore",
+			wantResult: `{"code":404,"message":"Failed restore scale","data":null,"errors":"your requested item is not found"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().Restore(gomock.Any(), "2022-02-01").Return(domain.ErrNotFound)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			NewScaleHandler(e, scaleMock)
+			req := httptest.NewRequest(http.MethodPost, test.args, nil)
+			rec := httptest.NewRecorder()
+
+			test.mock()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.wantResult, rec.Body.String())
+		})
+	}
+}
+
+func TestBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+	operations := []domain.ScaleOperation{
+		{Op: domain.ScaleOperationCreate, Date: "2022-02-01", Min: 45, Max: 50},
+		{Op: domain.ScaleOperationDelete, Date: "2022-02-02", Version: 1},
+	}
+
+	tests := []struct {
+		name       string
+		args       string
+		wantResult string
+		mock       func()
+	}{
+		{
+			name: "success",
+			args: `{"operations":[{"op":"create","date":"2022-02-01","min":45,"max":50},{"op":"delete","date":"2022-02-02","version":1}]}`,
+			wantResult: `{"code":200,"message":"Success batch scales","data":[{"op":"create","date":"2022-02-01","status":201,"scale":{"date":"2022-02-01T00:00:00+07:00","min":45,"max":50,"difference":5,"version":1}},{"op":"delete","date":"2022-02-02","status":200,"scale":{"date":"2022-02-02T00:00:00+07:00","min":0,"max":0,"difference":0,"version":0}}],"errors":null}
+`,
+			mock: func() {
+				scaleMock.EXPECT().Batch(gomock.Any(), operations).Return([]domain.ScaleOperationResult{
+					{Op: domain.ScaleOperationCreate, Date: "2022-02-01", Scale: &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1}},
+					{Op: domain.ScaleOperationDelete, Date: "2022-02-02", Scale: &domain.Scale{Date: date.AddDate(0, 0, 1)}},
+				}, nil)
+			},
+		},
+		{
+			name: "rollback",
+			args: `{"operations":[{"op":"create","date":"2022-02-01","min":45,"max":50},{"op":"delete","date":"2022-02-02","version":1}]}`,
+			wantResult: `{"code":412,"message":"Failed batch scales","data":[{"op":"create","date":"2022-02-01","status":424},{"op":"delete","date":"2022-02-02","status":412,"error":"your requested item has been modified"}],"errors":"your requested item has been modified"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().Batch(gomock.Any(), operations).Return([]domain.ScaleOperationResult{
+					{Op: domain.ScaleOperationCreate, Date: "2022-02-01"},
+					{Op: domain.ScaleOperationDelete, Date: "2022-02-02", Error: domain.ErrPreconditionFailed.Error()},
+				}, domain.ErrPreconditionFailed)
+			},
+		},
+		{
+			name: "empty",
+			args: `{"operations":[]}`,
+			wantResult: `{"code":400,"message":"Failed batch scales","data":null,"errors":"Key: 'ScaleBatchParam.Operations' Error:Field validation for 'Operations' failed on the 'min' tag"}
+`,
+			mock: func() {},
+		},
+		{
+			name: "unknown op",
+			args: `{"operations":[{"op":"upsert","date":"2022-02-01"}]}`,
+			wantResult: `{"code":400,"message":"Failed batch scales","data":null,"errors":"Key: 'ScaleBatchParam.Operations[0].Op' Error:Field validation for 'Op' failed on the 'oneof' tag"}
+`,
+			mock: func() {},
+		},
+		{
+			name: "error bind",
+			args: `{"operations":{}}`,
+			wantResult: `{"code":400,"message":"Failed batch scales","data":null,"errors":"code=400, message=Unmarshal type error: expected=[]domain.ScaleOperation, got=object, field=operations, offset=15"}
+`,
+			mock: func() {},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			NewScaleHandler(e, scaleMock)
+			req := httptest.NewRequest(http.MethodPost, "/scales/batch", strings.NewReader(test.args))
+			req.Header.Set("content-type", "application/json")
+			rec := httptest.NewRecorder()
+
+			test.mock()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.wantResult, rec.Body.String())
+		})
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -231,7 +499,7 @@ func TestUpdate(t *testing.T) {
 			wantResult: `{"code":200,"message":"Success update scale","data":null,"errors":null}
 `,
 			mock: func() {
-				scaleMock.EXPECT().Update(&domain.Scale{
+				scaleMock.EXPECT().Update(gomock.Any(), &domain.Scale{
 					Date: date,
 					Min:  45,
 					Max:  50,
@@ -244,7 +512,7 @@ func TestUpdate(t *testing.T) {
 			wantResult: `{"code":500,"message":"Failed update scale","data":null,"errors":"some error"}
 `,
 			mock: func() {
-				scaleMock.EXPECT().Update(&domain.Scale{
+				scaleMock.EXPECT().Update(gomock.Any(), &domain.Scale{
 					Date: date,
 					Min:  45,
 					Max:  50,
@@ -292,7 +560,7 @@ func TestDeleteScale(t *testing.T) {
 `,
 			wantErr: false,
 			mock: func() {
-				scaleMock.EXPECT().Delete("2022-02-01").Return(nil)
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", 0).Return(nil)
 			},
 		},
 		{
@@ -302,9 +570,17 @@ func TestDeleteScale(t *testing.T) {
 `,
 			wantErr: true,
 			mock: func() {
-				scaleMock.EXPECT().Delete("2022-02-01").Return(errors.New("some error"))
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", 0).Return(errors.New("some error"))
 			},
 		},
+		{
+			name: "error param",
+			args: `?date=date`,
+			wantResult: `{"code":400,"message":"Failed delete scales","data":null,"errors":"parsing time \"date\" as \"2006-01-02\": cannot parse \"date\" as \"2006\""}
+`,
+			wantErr: true,
+			mock:    func() {},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -325,3 +601,361 @@ func TestDeleteScale(t *testing.T) {
 		})
 	}
 }
+
+func TestGetChart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+
+	tests := []struct {
+		name        string
+		args        string
+		wantCode    int
+		wantType    string
+		wantContain string
+		mock        func()
+	}{
+		{
+			name:        "success",
+			args:        `?from=2022-02-01&to=2022-02-28&moving_average=2&goal=45`,
+			wantCode:    http.StatusOK,
+			wantType:    "image/svg+xml",
+			wantContain: `class="goal"`,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(&domain.ScaleResponse{
+					Scales: []domain.Scale{
+						{
+							Date:       date.AddDate(0, 0, 1),
+							Min:        47,
+							Max:        50,
+							Difference: 3,
+						},
+						{
+							Date:       date,
+							Min:        50,
+							Max:        53,
+							Difference: 3,
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:        "error param",
+			args:        `?from=date`,
+			wantCode:    http.StatusBadRequest,
+			wantType:    echo.MIMEApplicationJSONCharsetUTF8,
+			wantContain: `{"code":400,"message":"Failed get chart"`,
+			mock:        func() {},
+		},
+		{
+			name:        "goal not finite",
+			args:        `?goal=NaN`,
+			wantCode:    http.StatusBadRequest,
+			wantType:    echo.MIMEApplicationJSONCharsetUTF8,
+			wantContain: `{"code":400,"message":"Failed get chart"`,
+			mock:        func() {},
+		},
+		{
+			name:        "error",
+			wantCode:    http.StatusInternalServerError,
+			wantType:    echo.MIMEApplicationJSONCharsetUTF8,
+			wantContain: `{"code":500,"message":"Failed get chart","data":null,"errors":"some error"}`,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/scales/chart.svg%v", test.args), nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			h := scaleHandler{
+				scaleUsecase: scaleMock,
+			}
+
+			test.mock()
+
+			if assert.NoError(t, h.GetChart(c)) {
+				assert.Equal(t, test.wantCode, rec.Code)
+				assert.Equal(t, test.wantType, rec.Header().Get(echo.HeaderContentType))
+				assert.Contains(t, rec.Body.String(), test.wantContain)
+			}
+		})
+	}
+}
+
+func TestGetReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2018, 8, 22, 0, 0, 0, 0, helper.GetLocation())
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+
+	tests := []struct {
+		name        string
+		args        string
+		wantCode    int
+		wantType    string
+		wantContain string
+		mock        func()
+	}{
+		{
+			name:        "success",
+			args:        `?month=2018-08&user=Budi`,
+			wantCode:    http.StatusOK,
+			wantType:    "application/pdf",
+			wantContain: "Rabu, 22 Agustus 2018",
+			mock: func() {
+				scaleMock.EXPECT().GetScalesInRange(gomock.Any(), "2018-08-01", "2018-08-31").Return([]domain.Scale{
+					{
+						Date:       date,
+						Min:        49,
+						Max:        50,
+						Difference: 1,
+					},
+				}, nil)
+				scaleMock.EXPECT().GetSummary(gomock.Any(), "2018-08-01", "2018-08-31").Return(&domain.ScaleSummary{
+					Count: 1,
+					Average: &domain.ScaleAverrage{
+						Min:        49,
+						Max:        50,
+						Difference: 1,
+					},
+				}, nil)
+			},
+		},
+		{
+			name:        "error param",
+			args:        `?month=2018-08-22`,
+			wantCode:    http.StatusBadRequest,
+			wantType:    echo.MIMEApplicationJSONCharsetUTF8,
+			wantContain: `{"code":400,"message":"Failed get report"`,
+			mock:        func() {},
+		},
+		{
+			name:        "error",
+			args:        `?month=2018-08`,
+			wantCode:    http.StatusInternalServerError,
+			wantType:    echo.MIMEApplicationJSONCharsetUTF8,
+			wantContain: `{"code":500,"message":"Failed get report","data":null,"errors":"some error"}`,
+			mock: func() {
+				scaleMock.EXPECT().GetScalesInRange(gomock.Any(), "2018-08-01", "2018-08-31").Return(nil, errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/scales/report.pdf%v", test.args), nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			h := scaleHandler{
+				scaleUsecase: scaleMock,
+			}
+
+			test.mock()
+
+			if assert.NoError(t, h.GetReport(c)) {
+				assert.Equal(t, test.wantCode, rec.Code)
+				assert.Equal(t, test.wantType, rec.Header().Get(echo.HeaderContentType))
+				assert.Contains(t, rec.Body.String(), test.wantContain)
+			}
+		})
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       string
+		wantResult int
+	}{
+		{
+			name:       "absent",
+			args:       "",
+			wantResult: 0,
+		},
+		{
+			name:       "any",
+			args:       "*",
+			wantResult: 0,
+		},
+		{
+			name:       "strong",
+			args:       `"3"`,
+			wantResult: 3,
+		},
+		{
+			name:       "weak",
+			args:       `W/"3"`,
+			wantResult: -1,
+		},
+		{
+			name:       "malformed",
+			args:       `"three"`,
+			wantResult: -1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPatch, "/scale", nil)
+			req.Header.Set(common.HeaderIfMatch, test.args)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			assert.Equal(t, test.wantResult, ifMatch(c))
+		})
+	}
+}
+
+func TestETag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date, _ := time.Parse(common.TimeLayout, "2022-02-01")
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+
+	type args struct {
+		method  string
+		target  string
+		ifMatch string
+		body    string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantCode int
+		wantETag string
+		mock     func()
+	}{
+		{
+			name: "get",
+			args: args{
+				method: http.MethodGet,
+				target: "/scale?date=2022-02-01",
+			},
+			wantCode: http.StatusOK,
+			wantETag: `"3"`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{
+					{Date: date, Min: 47, Max: 50, Difference: 3, Version: 3},
+					{Date: date, Min: 48, Max: 50, Difference: 2, Version: 2},
+				}, nil)
+			},
+		},
+		{
+			name: "get v2 without reading",
+			args: args{
+				method: http.MethodGet,
+				target: "/v2/scales/2022-02-01",
+			},
+			wantCode: http.StatusNotFound,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{}, nil)
+			},
+		},
+		{
+			name: "update",
+			args: args{
+				method:  http.MethodPatch,
+				target:  "/scale",
+				ifMatch: `"3"`,
+				body:    `{"date":"2022-02-01","min":45,"max":50}`,
+			},
+			wantCode: http.StatusOK,
+			wantETag: `"4"`,
+			mock: func() {
+				scaleMock.EXPECT().Update(gomock.Any(), &domain.Scale{
+					Date:    date,
+					Min:     45,
+					Max:     50,
+					Version: 3,
+				}).DoAndReturn(func(ctx context.Context, param *domain.Scale) error {
+					param.Version = 4
+					return nil
+				})
+			},
+		},
+		{
+			name: "update stale",
+			args: args{
+				method:  http.MethodPatch,
+				target:  "/scale",
+				ifMatch: `"2"`,
+				body:    `{"date":"2022-02-01","min":45,"max":50}`,
+			},
+			wantCode: http.StatusPreconditionFailed,
+			mock: func() {
+				scaleMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(domain.ErrPreconditionFailed)
+			},
+		},
+		{
+			name: "patch v2 stale",
+			args: args{
+				method:  http.MethodPatch,
+				target:  "/v2/scales/2022-02-01",
+				ifMatch: `"2"`,
+				body:    `{"min":45}`,
+			},
+			wantCode: http.StatusPreconditionFailed,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{
+					{Date: date, Min: 47, Max: 50, Difference: 3, Version: 3},
+				}, nil)
+				scaleMock.EXPECT().Update(gomock.Any(), &domain.Scale{
+					Date:    date,
+					Min:     45,
+					Max:     50,
+					Version: 2,
+				}).Return(domain.ErrPreconditionFailed)
+			},
+		},
+		{
+			name: "delete",
+			args: args{
+				method:  http.MethodDelete,
+				target:  "/scale?date=2022-02-01",
+				ifMatch: `"3"`,
+			},
+			wantCode: http.StatusOK,
+			mock: func() {
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", 3).Return(nil)
+			},
+		},
+		{
+			name: "delete weak",
+			args: args{
+				method:  http.MethodDelete,
+				target:  "/scale?date=2022-02-01",
+				ifMatch: `W/"3"`,
+			},
+			wantCode: http.StatusPreconditionFailed,
+			mock: func() {
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", -1).Return(domain.ErrPreconditionFailed)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			NewScaleHandler(e, scaleMock)
+			req := httptest.NewRequest(test.args.method, test.args.target, strings.NewReader(test.args.body))
+			req.Header.Set("content-type", "application/json")
+			if test.args.ifMatch != "" {
+				req.Header.Set(common.HeaderIfMatch, test.args.ifMatch)
+			}
+			rec := httptest.NewRecorder()
+
+			test.mock()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.wantCode, rec.Code)
+			assert.Equal(t, test.wantETag, rec.Header().Get(common.HeaderETag))
+		})
+	}
+}
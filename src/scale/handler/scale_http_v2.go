@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+)
+
+func (h *scaleHandler) registerV2(r router) {
+	r.GET("/scales", h.GetScales)
+	r.POST("/scales", h.CreateV2, helper.BodyLimit(scaleBodyLimit))
+	r.GET("/scales/:date", h.GetScaleV2)
+	r.PUT("/scales/:date", h.ReplaceV2, helper.BodyLimit(scaleBodyLimit))
+	r.PATCH("/scales/:date", h.PatchV2, helper.BodyLimit(scaleBodyLimit))
+	r.DELETE("/scales/:date", h.DeleteV2)
+	r.GET("/scales/:date/history", h.GetHistory)
+	r.GET("/scales/trash", h.GetTrash)
+	r.POST("/scales/:date/restore", h.Restore)
+	r.POST("/scales/batch", h.Batch, helper.BodyLimit(DefaultBodyLimit))
+}
+
+type scalePatch struct {
+	Min *int `json:"min"`
+	Max *int `json:"max"`
+}
+
+func (h *scaleHandler) CreateV2(c echo.Context) error {
+	payload := &domain.ScaleParam{}
+	err := c.Bind(payload)
+	if err != nil {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed create scale", nil, err.Error()))
+	}
+	date, err := time.Parse(common.TimeLayout, payload.Date)
+	if err != nil {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed create scale", nil, err.Error()))
+	}
+
+	scale := &domain.Scale{
+		Date: date,
+		Min:  payload.Min,
+		Max:  payload.Max,
+	}
+	err = h.scaleUsecase.Create(c.Request().Context(), scale)
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed create scale", nil, err.Error()))
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, c.Path()+"/"+payload.Date)
+	setETag(c, *scale)
+	return c.JSON(http.StatusCreated, helper.Response(http.StatusCreated, "Success create scale", scale, nil))
+}
+
+// findScales returns the readings of the :date path param, ErrNotFound when it
+// has none.
+func (h *scaleHandler) findScales(c echo.Context) (time.Time, []domain.Scale, error) {
+	date, err := time.Parse(common.TimeLayout, c.Param("date"))
+	if err != nil {
+		return date, nil, err
+	}
+
+	scales, err := h.scaleUsecase.GetScale(c.Request().Context(), c.Param("date"))
+	if err != nil {
+		return date, nil, err
+	}
+	if len(scales) == 0 {
+		return date, nil, domain.ErrNotFound
+	}
+
+	return date, scales, nil
+}
+
+// findStatusCode is helper.GetStatusCode for the errors of findScales, an
+// invalid date is the client's fault.
+func findStatusCode(err error) int {
+	var parseErr *time.ParseError
+	if errors.As(err, &parseErr) {
+		return http.StatusBadRequest
+	}
+	return helper.GetStatusCode(err)
+}
+
+// currentVersion is the version an update of scales is conditional on, the
+// If-Match one or else the one read, so a write in between fails rather than
+// being overwritten.
+func currentVersion(c echo.Context, scales []domain.Scale) int {
+	version := ifMatch(c)
+	if version == 0 {
+		for _, scale := range scales {
+			if scale.Version > version {
+				version = scale.Version
+			}
+		}
+	}
+	return version
+}
+
+func (h *scaleHandler) GetScaleV2(c echo.Context) error {
+	_, scales, err := h.findScales(c)
+	if err != nil {
+		code := findStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed get scale", nil, err.Error()))
+	}
+
+	setETag(c, scales...)
+	data := helper.Response(200, "Success get scale", scales, nil)
+	return c.JSON(http.StatusOK, data)
+}
+
+func (h *scaleHandler) ReplaceV2(c echo.Context) error {
+	date, scales, err := h.findScales(c)
+	if err != nil {
+		code := findStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed update scale", nil, err.Error()))
+	}
+
+	patch := &scalePatch{}
+	decoder := json.NewDecoder(c.Request().Body)
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(patch)
+	if err == nil && (patch.Min == nil || patch.Max == nil) {
+		err = domain.ErrBadParamInput
+	}
+	if err != nil {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed update scale", nil, err.Error()))
+	}
+
+	return h.updateV2(c, date, currentVersion(c, scales), patch)
+}
+
+func (h *scaleHandler) PatchV2(c echo.Context) error {
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	if !strings.HasPrefix(contentType, common.MIMEApplicationMergePatchJSON) && !strings.HasPrefix(contentType, echo.MIMEApplicationJSON) {
+		code := http.StatusUnsupportedMediaType
+		return c.JSON(code, helper.Response(code, "Failed update scale", nil, "content type must be "+common.MIMEApplicationMergePatchJSON))
+	}
+
+	date, scales, err := h.findScales(c)
+	if err != nil {
+		code := findStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed update scale", nil, err.Error()))
+	}
+
+	patch, err := mergeScalePatch(scales[0], c.Request().Body)
+	if err != nil {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed update scale", nil, err.Error()))
+	}
+
+	return h.updateV2(c, date, currentVersion(c, scales), patch)
+}
+
+func mergeScalePatch(current domain.Scale, body io.Reader) (*scalePatch, error) {
+	var document interface{}
+	err := json.NewDecoder(body).Decode(&document)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := document.(map[string]interface{}); !ok {
+		return nil, domain.ErrBadParamInput
+	}
+
+	merged, err := json.Marshal(helper.MergePatch(map[string]interface{}{
+		"min": current.Min,
+		"max": current.Max,
+	}, document))
+	if err != nil {
+		return nil, err
+	}
+
+	patch := &scalePatch{}
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(patch)
+	if err != nil {
+		return nil, err
+	}
+	if patch.Min == nil || patch.Max == nil {
+		return nil, domain.ErrBadParamInput
+	}
+
+	return patch, nil
+}
+
+func (h *scaleHandler) updateV2(c echo.Context, date time.Time, version int, patch *scalePatch) error {
+	scale := &domain.Scale{
+		Date:    date,
+		Min:     *patch.Min,
+		Max:     *patch.Max,
+		Version: version,
+	}
+	err := h.scaleUsecase.Update(c.Request().Context(), scale)
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed update scale", nil, err.Error()))
+	}
+
+	setETag(c, *scale)
+	data := helper.Response(200, "Success update scale", scale, nil)
+	return c.JSON(http.StatusOK, data)
+}
+
+func (h *scaleHandler) DeleteV2(c echo.Context) error {
+	_, _, err := h.findScales(c)
+	if err != nil {
+		code := findStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed delete scale", nil, err.Error()))
+	}
+
+	err = h.scaleUsecase.Delete(c.Request().Context(), c.Param("date"), ifMatch(c))
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed delete scale", nil, err.Error()))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,488 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateV2(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date, _ := time.Parse(common.TimeLayout, "2022-02-01")
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+
+	tests := []struct {
+		name         string
+		args         string
+		wantCode     int
+		wantLocation string
+		wantResult   string
+		mock         func()
+	}{
+		{
+			name:         "success",
+			args:         `{"date":"2022-02-01","min":45,"max":50}`,
+			wantCode:     http.StatusCreated,
+			wantLocation: "/v2/scales/2022-02-01",
+			wantResult: `{"code":201,"message":"Success create scale","data":{"date":"2022-02-01T00:00:00Z","min":45,"max":50,"difference":5,"version":0},"errors":null}
+`,
+			mock: func() {
+				scaleMock.EXPECT().Create(gomock.Any(), &domain.Scale{
+					Date: date,
+					Min:  45,
+					Max:  50,
+				}).DoAndReturn(func(ctx context.Context, param *domain.Scale) error {
+					param.Difference = param.Max - param.Min
+					return nil
+				})
+			},
+		},
+		{
+			name:     "error param",
+			args:     `{"date":"date","min":45,"max":50}`,
+			wantCode: http.StatusBadRequest,
+			wantResult: `{"code":400,"message":"Failed create scale","data":null,"errors":"parsing time \"date\" as \"2006-01-02\": cannot parse \"date\" as \"2006\""}
+`,
+			mock: func() {},
+		},
+		{
+			name:     "min above max",
+			args:     `{"date":"2022-02-01","min":50,"max":45}`,
+			wantCode: http.StatusBadRequest,
+			wantResult: `{"code":400,"message":"Failed create scale","data":null,"errors":"Min. greater than max."}
+`,
+			mock: func() {
+				scaleMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.ErrMinAboveMax)
+			},
+		},
+		{
+			name:     "error",
+			args:     `{"date":"2022-02-01","min":45,"max":50}`,
+			wantCode: http.StatusInternalServerError,
+			wantResult: `{"code":500,"message":"Failed create scale","data":null,"errors":"some error"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/v2/scales", strings.NewReader(test.args))
+			req.Header.Set("content-type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/v2/scales")
+			h := scaleHandler{
+				scaleUsecase: scaleMock,
+			}
+
+			test.mock()
+
+			if assert.NoError(t, h.CreateV2(c)) {
+				assert.Equal(t, test.wantCode, rec.Code)
+				assert.Equal(t, test.wantLocation, rec.Header().Get(echo.HeaderLocation))
+				assert.Equal(t, test.wantResult, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetScaleV2(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date, _ := time.Parse(common.TimeLayout, "2022-02-01")
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+
+	tests := []struct {
+		name       string
+		args       string
+		wantCode   int
+		wantResult string
+		mock       func()
+	}{
+		{
+			name:     "success",
+			args:     "2022-02-01",
+			wantCode: http.StatusOK,
+			wantResult: `{"code":200,"message":"Success get scale","data":[{"date":"2022-02-01T00:00:00Z","min":47,"max":50,"difference":3,"version":0}],"errors":null}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{
+					{
+						Date:       date,
+						Min:        47,
+						Max:        50,
+						Difference: 3,
+					},
+				}, nil)
+			},
+		},
+		{
+			name:     "not found",
+			args:     "2022-02-01",
+			wantCode: http.StatusNotFound,
+			wantResult: `{"code":404,"message":"Failed get scale","data":null,"errors":"your requested item is not found"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{}, nil)
+			},
+		},
+		{
+			name:     "error param",
+			args:     "date",
+			wantCode: http.StatusBadRequest,
+			wantResult: `{"code":400,"message":"Failed get scale","data":null,"errors":"parsing time \"date\" as \"2006-01-02\": cannot parse \"date\" as \"2006\""}
+`,
+			mock: func() {},
+		},
+		{
+			name:     "error",
+			args:     "2022-02-01",
+			wantCode: http.StatusInternalServerError,
+			wantResult: `{"code":500,"message":"Failed get scale","data":null,"errors":"some error"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return(nil, errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/v2/scales/"+test.args, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("date")
+			c.SetParamValues(test.args)
+			h := scaleHandler{
+				scaleUsecase: scaleMock,
+			}
+
+			test.mock()
+
+			if assert.NoError(t, h.GetScaleV2(c)) {
+				assert.Equal(t, test.wantCode, rec.Code)
+				assert.Equal(t, test.wantResult, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestReplaceV2(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date, _ := time.Parse(common.TimeLayout, "2022-02-01")
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+
+	tests := []struct {
+		name       string
+		args       string
+		wantCode   int
+		wantResult string
+		mock       func()
+	}{
+		{
+			name:     "success",
+			args:     `{"min":46,"max":50}`,
+			wantCode: http.StatusOK,
+			wantResult: `{"code":200,"message":"Success update scale","data":{"date":"2022-02-01T00:00:00Z","min":46,"max":50,"difference":4,"version":0},"errors":null}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5}}, nil)
+				scaleMock.EXPECT().Update(gomock.Any(), &domain.Scale{
+					Date: date,
+					Min:  46,
+					Max:  50,
+				}).DoAndReturn(func(ctx context.Context, param *domain.Scale) error {
+					param.Difference = param.Max - param.Min
+					return nil
+				})
+			},
+		},
+		{
+			name:     "changed meanwhile",
+			args:     `{"min":46,"max":50}`,
+			wantCode: http.StatusPreconditionFailed,
+			wantResult: `{"code":412,"message":"Failed update scale","data":null,"errors":"your requested item has been modified"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5, Version: 3}}, nil)
+				scaleMock.EXPECT().Update(gomock.Any(), &domain.Scale{
+					Date:    date,
+					Min:     46,
+					Max:     50,
+					Version: 3,
+				}).Return(domain.ErrPreconditionFailed)
+			},
+		},
+		{
+			name:     "missing field",
+			args:     `{"min":46}`,
+			wantCode: http.StatusBadRequest,
+			wantResult: `{"code":400,"message":"Failed update scale","data":null,"errors":"given param is not valid"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5}}, nil)
+			},
+		},
+		{
+			name:     "not found",
+			args:     `{"min":46,"max":50}`,
+			wantCode: http.StatusNotFound,
+			wantResult: `{"code":404,"message":"Failed update scale","data":null,"errors":"your requested item is not found"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{}, nil)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, "/v2/scales/2022-02-01", strings.NewReader(test.args))
+			req.Header.Set("content-type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("date")
+			c.SetParamValues("2022-02-01")
+			h := scaleHandler{
+				scaleUsecase: scaleMock,
+			}
+
+			test.mock()
+
+			if assert.NoError(t, h.ReplaceV2(c)) {
+				assert.Equal(t, test.wantCode, rec.Code)
+				assert.Equal(t, test.wantResult, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestPatchV2(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date, _ := time.Parse(common.TimeLayout, "2022-02-01")
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+	current := []domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5}}
+
+	type args struct {
+		contentType string
+		body        string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantCode   int
+		wantResult string
+		mock       func()
+	}{
+		{
+			name: "success",
+			args: args{
+				contentType: common.MIMEApplicationMergePatchJSON,
+				body:        `{"max":52}`,
+			},
+			wantCode: http.StatusOK,
+			wantResult: `{"code":200,"message":"Success update scale","data":{"date":"2022-02-01T00:00:00Z","min":45,"max":52,"difference":7,"version":0},"errors":null}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return(current, nil)
+				scaleMock.EXPECT().Update(gomock.Any(), &domain.Scale{
+					Date: date,
+					Min:  45,
+					Max:  52,
+				}).DoAndReturn(func(ctx context.Context, param *domain.Scale) error {
+					param.Difference = param.Max - param.Min
+					return nil
+				})
+			},
+		},
+		{
+			name: "changed meanwhile",
+			args: args{
+				contentType: common.MIMEApplicationMergePatchJSON,
+				body:        `{"max":52}`,
+			},
+			wantCode: http.StatusPreconditionFailed,
+			wantResult: `{"code":412,"message":"Failed update scale","data":null,"errors":"your requested item has been modified"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5, Version: 3}}, nil)
+				scaleMock.EXPECT().Update(gomock.Any(), &domain.Scale{
+					Date:    date,
+					Min:     45,
+					Max:     52,
+					Version: 3,
+				}).Return(domain.ErrPreconditionFailed)
+			},
+		},
+		{
+			name: "remove field",
+			args: args{
+				contentType: common.MIMEApplicationMergePatchJSON,
+				body:        `{"min":null}`,
+			},
+			wantCode: http.StatusBadRequest,
+			wantResult: `{"code":400,"message":"Failed update scale","data":null,"errors":"given param is not valid"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return(current, nil)
+			},
+		},
+		{
+			name: "unknown field",
+			args: args{
+				contentType: common.MIMEApplicationMergePatchJSON,
+				body:        `{"difference":1}`,
+			},
+			wantCode: http.StatusBadRequest,
+			wantResult: `{"code":400,"message":"Failed update scale","data":null,"errors":"json: unknown field \"difference\""}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return(current, nil)
+			},
+		},
+		{
+			name: "not an object",
+			args: args{
+				contentType: common.MIMEApplicationMergePatchJSON,
+				body:        `[1]`,
+			},
+			wantCode: http.StatusBadRequest,
+			wantResult: `{"code":400,"message":"Failed update scale","data":null,"errors":"given param is not valid"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return(current, nil)
+			},
+		},
+		{
+			name: "unsupported media type",
+			args: args{
+				contentType: echo.MIMETextPlain,
+				body:        `{"max":52}`,
+			},
+			wantCode: http.StatusUnsupportedMediaType,
+			wantResult: `{"code":415,"message":"Failed update scale","data":null,"errors":"content type must be application/merge-patch+json"}
+`,
+			mock: func() {},
+		},
+		{
+			name: "error",
+			args: args{
+				contentType: echo.MIMEApplicationJSON,
+				body:        `{"max":52}`,
+			},
+			wantCode: http.StatusInternalServerError,
+			wantResult: `{"code":500,"message":"Failed update scale","data":null,"errors":"some error"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return(current, nil)
+				scaleMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPatch, "/v2/scales/2022-02-01", strings.NewReader(test.args.body))
+			req.Header.Set("content-type", test.args.contentType)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("date")
+			c.SetParamValues("2022-02-01")
+			h := scaleHandler{
+				scaleUsecase: scaleMock,
+			}
+
+			test.mock()
+
+			if assert.NoError(t, h.PatchV2(c)) {
+				assert.Equal(t, test.wantCode, rec.Code)
+				assert.Equal(t, test.wantResult, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestDeleteV2(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date, _ := time.Parse(common.TimeLayout, "2022-02-01")
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+
+	tests := []struct {
+		name       string
+		wantCode   int
+		wantResult string
+		mock       func()
+	}{
+		{
+			name:       "success",
+			wantCode:   http.StatusNoContent,
+			wantResult: "",
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5}}, nil)
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", 0).Return(nil)
+			},
+		},
+		{
+			name:     "not found",
+			wantCode: http.StatusNotFound,
+			wantResult: `{"code":404,"message":"Failed delete scale","data":null,"errors":"your requested item is not found"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{}, nil)
+			},
+		},
+		{
+			name:     "error",
+			wantCode: http.StatusInternalServerError,
+			wantResult: `{"code":500,"message":"Failed delete scale","data":null,"errors":"some error"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), "2022-02-01").Return([]domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5}}, nil)
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", 0).Return(errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodDelete, "/v2/scales/2022-02-01", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("date")
+			c.SetParamValues("2022-02-01")
+			h := scaleHandler{
+				scaleUsecase: scaleMock,
+			}
+
+			test.mock()
+
+			if assert.NoError(t, h.DeleteV2(c)) {
+				assert.Equal(t, test.wantCode, rec.Code)
+				assert.Equal(t, test.wantResult, rec.Body.String())
+			}
+		})
+	}
+}
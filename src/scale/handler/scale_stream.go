@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+)
+
+const (
+	headerLastEventID = "Last-Event-ID"
+	eventReset        = "reset"
+)
+
+var keepAlive = 15 * time.Second
+
+type scaleStreamHandler struct {
+	scaleStream domain.ScaleStream
+}
+
+func NewScaleStreamHandler(e *echo.Echo, scaleStream domain.ScaleStream) {
+	handler := &scaleStreamHandler{
+		scaleStream: scaleStream,
+	}
+
+	e.GET("/scales/stream", handler.Stream)
+}
+
+func writeEvent(w *echo.Response, event domain.ScaleStreamEvent) error {
+	data, err := json.Marshal(event.ScaleEvent)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+func (h *scaleStreamHandler) Stream(c echo.Context) error {
+	lastEventID := c.Request().Header.Get(headerLastEventID)
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("last_event_id")
+	}
+	var id uint64
+	if lastEventID != "" {
+		var err error
+		id, err = strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			code := http.StatusBadRequest
+			return c.JSON(code, helper.Response(code, "Failed stream scales", nil, domain.ErrBadParamInput.Error()))
+		}
+	}
+
+	subscription := h.scaleStream.Subscribe(id)
+	defer h.scaleStream.Unsubscribe(subscription)
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// the log no longer holds every event the client missed, it has to reload.
+	// The empty id clears the stale id the browser would resume from.
+	if subscription.Missed {
+		fmt.Fprintf(w, "id: \nevent: %s\ndata: {}\n\n", eventReset)
+	}
+	for _, event := range subscription.Backlog {
+		err := writeEvent(w, event)
+		if err != nil {
+			return nil
+		}
+	}
+	w.Flush()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-subscription.Events:
+			// dropped for falling behind or closed on shutdown, the client
+			// reconnects with its last id
+			if !ok {
+				if !subscription.Closed {
+					helper.GetLogger(c.Request().Context()).Warn("stream subscriber dropped")
+				}
+				return nil
+			}
+			err := writeEvent(w, event)
+			if err != nil {
+				return nil
+			}
+		}
+		w.Flush()
+	}
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/scale/src/scale/stream"
+	"github.com/stretchr/testify/assert"
+)
+
+// readEvent reads the lines of one event, up to the blank line ending it.
+func readEvent(t *testing.T, r *bufio.Reader) []string {
+	lines := []string{}
+	for {
+		line, err := r.ReadString('\n')
+		if !assert.NoError(t, err) {
+			return lines
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestStream(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	occurredAt := time.Date(2022, 2, 1, 7, 0, 0, 0, helper.GetLocation())
+	scaleStream := stream.NewScaleStream(2)
+
+	e := echo.New()
+	NewScaleStreamHandler(e, scaleStream)
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	for i := 0; i < 3; i++ {
+		scaleStream.Publish(domain.ScaleEvent{
+			Type:       domain.ScaleCreated,
+			Scale:      domain.Scale{Date: date.AddDate(0, 0, i), Min: 47, Max: 50, Difference: 3},
+			OccurredAt: occurredAt,
+		})
+	}
+
+	t.Run("resume", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/scales/stream", nil)
+		req.Header.Set(headerLastEventID, "2")
+		res, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/event-stream", res.Header.Get(echo.HeaderContentType))
+
+		r := bufio.NewReader(res.Body)
+		assert.Equal(t, []string{
+			"id: 3",
+			"event: scale.created",
+			`data: {"type":"scale.created","scale":{"date":"2022-02-03T00:00:00+07:00","min":47,"max":50,"difference":3,"version":0},"occurred_at":"2022-02-01T07:00:00+07:00"}`,
+		}, readEvent(t, r))
+
+		scaleStream.Publish(domain.ScaleEvent{
+			Type:       domain.ScaleDeleted,
+			Scale:      domain.Scale{Date: date},
+			OccurredAt: occurredAt,
+		})
+		assert.Equal(t, []string{
+			"id: 4",
+			"event: scale.deleted",
+			`data: {"type":"scale.deleted","scale":{"date":"2022-02-01T00:00:00+07:00","min":0,"max":0,"difference":0,"version":0},"occurred_at":"2022-02-01T07:00:00+07:00"}`,
+		}, readEvent(t, r))
+	})
+
+	t.Run("missed", func(t *testing.T) {
+		res, err := http.Get(server.URL + "/scales/stream?last_event_id=1")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer res.Body.Close()
+
+		r := bufio.NewReader(res.Body)
+		assert.Equal(t, []string{"id: ", "event: reset", "data: {}"}, readEvent(t, r))
+		assert.Equal(t, "id: 3", readEvent(t, r)[0])
+		assert.Equal(t, "id: 4", readEvent(t, r)[0])
+	})
+
+	t.Run("error param", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/scales/stream", nil)
+		req.Header.Set(headerLastEventID, "id")
+		res, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func TestStreamClose(t *testing.T) {
+	scaleStream := stream.NewScaleStream(stream.DefaultLogSize)
+
+	e := echo.New()
+	NewScaleStreamHandler(e, scaleStream)
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/scales/stream")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer res.Body.Close()
+
+	scaleStream.Close()
+	// the handler returns, ending the response
+	_, err = ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+}
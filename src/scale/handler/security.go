@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/scale/src/common"
+)
+
+const contentSecurityPolicy = "default-src 'self'; frame-ancestors 'none'"
+
+var (
+	DefaultCORSMethods = []string{
+		http.MethodGet,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodPatch,
+		http.MethodDelete,
+	}
+	DefaultCORSHeaders = []string{
+		echo.HeaderContentType,
+		common.HeaderIfMatch,
+		common.HeaderAPIKey,
+		echo.HeaderXRequestID,
+	}
+
+	// browsers hide response headers from scripts unless listed, these are
+	// needed for conditional writes, support requests and backing off
+	corsExposeHeaders = strings.Join([]string{
+		common.HeaderETag,
+		echo.HeaderXRequestID,
+		common.HeaderRetryAfter,
+	}, ",")
+)
+
+type CORSConfig struct {
+	// "*" allows any origin, it cannot be combined with AllowCredentials
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORS lets browser scripts from the allowed origins call the API, answering
+// their preflight requests itself. Requests from other origins are served
+// without CORS headers, so browsers keep the responses from the scripts.
+func CORS(config CORSConfig) echo.MiddlewareFunc {
+	origins := map[string]bool{}
+	for _, origin := range config.AllowOrigins {
+		origins[origin] = true
+	}
+	methods := strings.Join(config.AllowMethods, ",")
+	headers := strings.Join(config.AllowHeaders, ",")
+	maxAge := strconv.Itoa(int(config.MaxAge.Seconds()))
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			header := c.Response().Header()
+			origin := req.Header.Get(echo.HeaderOrigin)
+			preflight := req.Method == http.MethodOptions && req.Header.Get(echo.HeaderAccessControlRequestMethod) != ""
+
+			header.Add(echo.HeaderVary, echo.HeaderOrigin)
+			if origin == "" || (!origins["*"] && !origins[origin]) {
+				if preflight {
+					return c.NoContent(http.StatusNoContent)
+				}
+				return next(c)
+			}
+
+			if origins["*"] {
+				header.Set(echo.HeaderAccessControlAllowOrigin, "*")
+			} else {
+				header.Set(echo.HeaderAccessControlAllowOrigin, origin)
+			}
+			if config.AllowCredentials {
+				header.Set(echo.HeaderAccessControlAllowCredentials, "true")
+			}
+			if !preflight {
+				header.Set(echo.HeaderAccessControlExposeHeaders, corsExposeHeaders)
+				return next(c)
+			}
+
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestMethod)
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestHeaders)
+			header.Set(echo.HeaderAccessControlAllowMethods, methods)
+			header.Set(echo.HeaderAccessControlAllowHeaders, headers)
+			if config.MaxAge > 0 {
+				header.Set(echo.HeaderAccessControlMaxAge, maxAge)
+			}
+			return c.NoContent(http.StatusNoContent)
+		}
+	}
+}
+
+// SecurityHeaders keeps browsers from sniffing content types, framing the UI
+// or leaking URLs in referrers. With hsts, served over TLS, it also tells
+// them to only come back over HTTPS.
+func SecurityHeaders(hsts bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+			header.Set(echo.HeaderXFrameOptions, "DENY")
+			header.Set(echo.HeaderContentSecurityPolicy, contentSecurityPolicy)
+			header.Set(common.HeaderReferrerPolicy, "no-referrer")
+			if hsts {
+				header.Set(echo.HeaderStrictTransportSecurity, "max-age=31536000; includeSubDomains")
+			}
+			return next(c)
+		}
+	}
+}
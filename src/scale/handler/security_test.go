@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	config := CORSConfig{
+		AllowOrigins:     []string{"https://dashboard.example.com"},
+		AllowMethods:     DefaultCORSMethods,
+		AllowHeaders:     DefaultCORSHeaders,
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}
+
+	type args struct {
+		config    CORSConfig
+		method    string
+		origin    string
+		preflight bool
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantCode   int
+		wantHeader http.Header
+	}{
+		{
+			name:     "same origin",
+			args:     args{config: config, method: http.MethodGet},
+			wantCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Vary": {"Origin"},
+			},
+		},
+		{
+			name:     "allowed origin",
+			args:     args{config: config, method: http.MethodGet, origin: "https://dashboard.example.com"},
+			wantCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Vary":                             {"Origin"},
+				"Access-Control-Allow-Origin":      {"https://dashboard.example.com"},
+				"Access-Control-Allow-Credentials": {"true"},
+				"Access-Control-Expose-Headers":    {"ETag,X-Request-ID,Retry-After"},
+			},
+		},
+		{
+			name:     "allowed preflight",
+			args:     args{config: config, method: http.MethodOptions, origin: "https://dashboard.example.com", preflight: true},
+			wantCode: http.StatusNoContent,
+			wantHeader: http.Header{
+				"Vary":                             {"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+				"Access-Control-Allow-Origin":      {"https://dashboard.example.com"},
+				"Access-Control-Allow-Credentials": {"true"},
+				"Access-Control-Allow-Methods":     {"GET,POST,PUT,PATCH,DELETE"},
+				"Access-Control-Allow-Headers":     {"Content-Type,If-Match,X-API-Key,X-Request-ID"},
+				"Access-Control-Max-Age":           {"3600"},
+			},
+		},
+		{
+			name:     "other origin",
+			args:     args{config: config, method: http.MethodGet, origin: "https://evil.example.com"},
+			wantCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Vary": {"Origin"},
+			},
+		},
+		{
+			name:     "other origin preflight",
+			args:     args{config: config, method: http.MethodOptions, origin: "https://evil.example.com", preflight: true},
+			wantCode: http.StatusNoContent,
+			wantHeader: http.Header{
+				"Vary": {"Origin"},
+			},
+		},
+		{
+			name: "any origin",
+			args: args{
+				config: CORSConfig{AllowOrigins: []string{"*"}},
+				method: http.MethodGet,
+				origin: "https://evil.example.com",
+			},
+			wantCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Vary":                          {"Origin"},
+				"Access-Control-Allow-Origin":   {"*"},
+				"Access-Control-Expose-Headers": {"ETag,X-Request-ID,Retry-After"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(test.args.method, "/scale", nil)
+			if test.args.origin != "" {
+				req.Header.Set(echo.HeaderOrigin, test.args.origin)
+			}
+			if test.args.preflight {
+				req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := CORS(test.args.config)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})(c)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantCode, rec.Code)
+			assert.Equal(t, test.wantHeader, rec.Header())
+		})
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     bool
+		wantHSTS string
+	}{
+		{
+			name: "http",
+		},
+		{
+			name:     "https",
+			args:     true,
+			wantHSTS: "max-age=31536000; includeSubDomains",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/scales", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := SecurityHeaders(test.args)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})(c)
+			assert.NoError(t, err)
+			assert.Equal(t, "nosniff", rec.Header().Get(echo.HeaderXContentTypeOptions))
+			assert.Equal(t, "DENY", rec.Header().Get(echo.HeaderXFrameOptions))
+			assert.Equal(t, "default-src 'self'; frame-ancestors 'none'", rec.Header().Get(echo.HeaderContentSecurityPolicy))
+			assert.Equal(t, "no-referrer", rec.Header().Get("Referrer-Policy"))
+			assert.Equal(t, test.wantHSTS, rec.Header().Get(echo.HeaderStrictTransportSecurity))
+		})
+	}
+}
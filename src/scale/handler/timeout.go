@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo"
+	"google.golang.org/grpc"
+)
+
+// streams are served for as long as their clients listen, a deadline would
+// cut every one of them off
+var untimedRoutes = map[string]bool{
+	"/scales/stream": true,
+}
+
+// Timeout ends the context of every request after timeout, storage then gives
+// up on it and the handlers answer 504.
+func Timeout(timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if untimedRoutes[c.Path()] {
+				return next(c)
+			}
+			req := c.Request()
+			ctx, cancel := context.WithTimeout(req.Context(), timeout)
+			defer cancel()
+			c.SetRequest(req.WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// TimeoutInterceptor is Timeout for gRPC, answering DeadlineExceeded. A
+// shorter deadline set by the client is kept.
+func TimeoutInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// TimeoutStreamInterceptor is TimeoutInterceptor for streaming calls, the
+// whole stream has to finish in time.
+func TimeoutStreamInterceptor(timeout time.Duration) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := context.WithTimeout(ss.Context(), timeout)
+		defer cancel()
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
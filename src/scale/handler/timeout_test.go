@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	"github.com/scale/src/domain"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/scale/src/proto/scalepb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// waitForDeadline stands in for storage giving up on a request running late.
+func waitForDeadline(ctx context.Context) (*domain.ScaleResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+	h := &scaleHandler{
+		scaleUsecase: scaleMock,
+	}
+
+	e := echo.New()
+	e.Use(Timeout(10 * time.Millisecond))
+	e.GET("/scales", h.GetScales)
+	e.GET("/scales/stream", func(c echo.Context) error {
+		_, ok := c.Request().Context().Deadline()
+		assert.False(t, ok)
+		return c.NoContent(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		args       string
+		wantCode   int
+		wantResult string
+		mock       func()
+	}{
+		{
+			name:     "timed out",
+			args:     "/scales",
+			wantCode: http.StatusGatewayTimeout,
+			wantResult: `{"code":504,"message":"Failed get scales","data":null,"errors":"context deadline exceeded"}
+`,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).DoAndReturn(waitForDeadline)
+			},
+		},
+		{
+			name:     "stream untimed",
+			args:     "/scales/stream",
+			wantCode: http.StatusOK,
+			mock:     func() {},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+
+			req := httptest.NewRequest(http.MethodGet, test.args, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.wantCode, rec.Code)
+			if test.wantResult != "" {
+				assert.Equal(t, test.wantResult, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestTimeoutInterceptor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+	client := newGRPCClient(t, scaleMock,
+		grpc.ChainUnaryInterceptor(TimeoutInterceptor(10*time.Millisecond)),
+		grpc.ChainStreamInterceptor(TimeoutStreamInterceptor(10*time.Millisecond)),
+	)
+	scaleMock.EXPECT().GetScales(gomock.Any()).DoAndReturn(waitForDeadline).Times(2)
+
+	_, err := client.GetScales(context.Background(), &scalepb.GetScalesRequest{})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	stream, err := client.ListScales(context.Background(), &scalepb.ListScalesRequest{})
+	if assert.NoError(t, err) {
+		_, err = stream.Recv()
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	}
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"github.com/labstack/echo"
+	"github.com/scale/src/helper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/scale/src/scale/handler"
+
+// Tracing starts a server span named after the route of every request,
+// continuing the trace of its traceparent header when there is one. Whether a
+// route matched is only known from the status, the span is named once served.
+func Tracing(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+		ctx, span := otel.Tracer(instrumentationName).Start(ctx, "HTTP "+req.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(helper.ServiceName, "", req)...),
+		)
+		defer span.End()
+		if span.SpanContext().IsValid() {
+			ctx = helper.WithLogger(ctx, helper.GetLogger(ctx).WithField("trace_id", span.SpanContext().TraceID().String()))
+		}
+		c.SetRequest(req.WithContext(ctx))
+
+		err := next(c)
+
+		status := responseStatus(c, err)
+		if route := route(c, status); route != "" {
+			span.SetName(req.Method + " " + route)
+			span.SetAttributes(semconv.HTTPRouteKey.String(route))
+		}
+		span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindServer))
+		return err
+	}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	tests := []struct {
+		name        string
+		args        string
+		path        string
+		wantName    string
+		wantStatus  codes.Code
+		wantTraceID string
+		code        int
+	}{
+		{
+			name:        "continued trace",
+			args:        "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			path:        "/scales/2022-02-01",
+			wantName:    "GET /scales/:date",
+			wantStatus:  codes.Unset,
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			code:        http.StatusOK,
+		},
+		{
+			name:       "server error",
+			path:       "/scales/2022-02-01",
+			wantName:   "GET /scales/:date",
+			wantStatus: codes.Error,
+			code:       http.StatusInternalServerError,
+		},
+		{
+			name:       "not found",
+			path:       "/unknown/path",
+			wantName:   "HTTP GET",
+			wantStatus: codes.Unset,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(Tracing)
+			e.GET("/scales/:date", func(c echo.Context) error {
+				assert.True(t, trace.SpanContextFromContext(c.Request().Context()).IsValid())
+				return c.NoContent(test.code)
+			})
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			if test.args != "" {
+				req.Header.Set("traceparent", test.args)
+			}
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			spans := recorder.Ended()
+			span := spans[len(spans)-1]
+			assert.Equal(t, test.wantName, span.Name())
+			assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+			assert.Equal(t, test.wantStatus, span.Status().Code)
+			if test.wantTraceID != "" {
+				assert.Equal(t, test.wantTraceID, span.SpanContext().TraceID().String())
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package maintenance
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+)
+
+// Fix rewrites the stored readings, returning how many it changed or removed.
+type Fix func(scales []domain.Scale) ([]domain.Scale, int)
+
+type Migration struct {
+	Name  string
+	Apply Fix
+}
+
+// Migrations bring readings stored by older versions up to what the current
+// code expects. Each one leaves readings it already migrated alone, so they
+// all run every time.
+var Migrations = []Migration{
+	{Name: "0001_versions", Apply: migrateVersions},
+}
+
+type Problem struct {
+	Date    string `json:"date"`
+	Version int    `json:"version"`
+	Reason  string `json:"reason"`
+}
+
+func day(scale domain.Scale) string {
+	return scale.Date.Format(common.TimeLayout)
+}
+
+// Validate reports the live readings breaking the rules writes keep. Several
+// readings on one day are not among them, writes allow it and Dedupe only
+// tidies up.
+func Validate(scales []domain.Scale) []Problem {
+	problems := []Problem{}
+	for _, scale := range scales {
+		if scale.DeletedAt != nil {
+			continue
+		}
+
+		problem := Problem{Date: day(scale), Version: scale.Version}
+		if scale.Max < scale.Min {
+			problem.Reason = fmt.Sprintf("max %d is below min %d", scale.Max, scale.Min)
+			problems = append(problems, problem)
+		}
+		if scale.Difference != scale.Max-scale.Min {
+			problem.Reason = fmt.Sprintf("difference is %d, want %d", scale.Difference, scale.Max-scale.Min)
+			problems = append(problems, problem)
+		}
+		if scale.Version == 0 {
+			problem.Reason = "no version, run the migrations"
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+// Dedupe keeps the newest live reading of every day, the one with the highest
+// version or else stored last. Deleted readings are kept for the trash.
+func Dedupe(scales []domain.Scale) ([]domain.Scale, int) {
+	newest := map[string]int{}
+	for i, scale := range scales {
+		if scale.DeletedAt != nil {
+			continue
+		}
+		if j, ok := newest[day(scale)]; !ok || scale.Version >= scales[j].Version {
+			newest[day(scale)] = i
+		}
+	}
+
+	deduped := make([]domain.Scale, 0, len(scales))
+	for i, scale := range scales {
+		if scale.DeletedAt == nil && newest[day(scale)] != i {
+			continue
+		}
+		deduped = append(deduped, scale)
+	}
+	return deduped, len(scales) - len(deduped)
+}
+
+// Recompute sets the Difference of every reading from its Min and Max.
+func Recompute(scales []domain.Scale) ([]domain.Scale, int) {
+	changed := 0
+	for i := range scales {
+		if scales[i].Difference != scales[i].Max-scales[i].Min {
+			scales[i].Difference = scales[i].Max - scales[i].Min
+			changed++
+		}
+	}
+	return scales, changed
+}
+
+// Migrate runs every migration in order, returning how many readings each
+// one changed.
+func Migrate(scales []domain.Scale) ([]domain.Scale, map[string]int) {
+	changed := map[string]int{}
+	for _, migration := range Migrations {
+		scales, changed[migration.Name] = migration.Apply(scales)
+	}
+	return scales, changed
+}
+
+// migrateVersions gives readings stored before versioning the next version of
+// their day, versions never repeat so it is above any other of that day.
+func migrateVersions(scales []domain.Scale) ([]domain.Scale, int) {
+	last := map[string]int{}
+	for _, scale := range scales {
+		if scale.Version > last[day(scale)] {
+			last[day(scale)] = scale.Version
+		}
+	}
+
+	changed := 0
+	for i := range scales {
+		if scales[i].Version == 0 {
+			scales[i].Version = last[day(scales[i])] + 1
+			changed++
+		}
+	}
+	return scales, changed
+}
+
+// Apply runs fix on every stored reading in one transaction, only writing the
+// result back when it changed something and dryRun is off.
+func Apply(ctx context.Context, repo domain.ScaleRepository, dryRun bool, fix Fix) (int, error) {
+	var changed int
+	err := repo.Transaction(ctx, func(tx domain.ScaleRepository) error {
+		snapshot, err := tx.Dump(ctx)
+		if err != nil {
+			return err
+		}
+		snapshot.Scales, changed = fix(snapshot.Scales)
+		if dryRun || changed == 0 {
+			return nil
+		}
+		return tx.Load(ctx, snapshot)
+	})
+	return changed, err
+}
+
+// InvalidBackupError refuses a backup whose readings break the rules writes
+// keep, restoring it would serve them as is.
+type InvalidBackupError struct {
+	Problems []Problem
+}
+
+func (e *InvalidBackupError) Error() string {
+	return fmt.Sprintf("backup has %d problems", len(e.Problems))
+}
+
+// checksum is the SHA-256 of the compact JSON of snapshot, the same however
+// the backup is indented.
+func checksum(snapshot *domain.ScaleSnapshot) (string, error) {
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+func NewBackup(snapshot *domain.ScaleSnapshot) (*domain.ScaleBackup, error) {
+	sum, err := checksum(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ScaleBackup{
+		Version:   domain.ScaleBackupVersion,
+		CreatedAt: helper.Now(),
+		Checksum:  sum,
+		Snapshot:  snapshot,
+	}, nil
+}
+
+func WriteBackup(w io.Writer, backup *domain.ScaleBackup) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(backup)
+}
+
+// ReadBackup decodes a backup of any known version as the current one,
+// refusing it when its checksum does not match.
+func ReadBackup(r io.Reader) (*domain.ScaleBackup, error) {
+	var backup struct {
+		domain.ScaleBackup
+		// version 1
+		Scales []domain.Scale `json:"scales"`
+	}
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&backup)
+	if err != nil {
+		return nil, err
+	}
+
+	switch backup.Version {
+	case 1:
+		return NewBackup(&domain.ScaleSnapshot{Scales: backup.Scales})
+	case domain.ScaleBackupVersion:
+		if backup.Snapshot == nil {
+			return nil, errors.New("backup has no snapshot")
+		}
+		sum, err := checksum(backup.Snapshot)
+		if err != nil {
+			return nil, err
+		}
+		if sum != backup.Checksum {
+			return nil, fmt.Errorf("backup checksum is %s, want %s", backup.Checksum, sum)
+		}
+		return &backup.ScaleBackup, nil
+	}
+	return nil, fmt.Errorf("backup version %d is not supported, want at most %d", backup.Version, domain.ScaleBackupVersion)
+}
+
+func Backup(ctx context.Context, repo domain.ScaleRepository) (*domain.ScaleBackup, error) {
+	snapshot, err := repo.Dump(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewBackup(snapshot)
+}
+
+// Restore migrates the snapshot of backup and replaces everything stored with
+// it, refusing it with an *InvalidBackupError when its readings do not
+// validate.
+func Restore(ctx context.Context, repo domain.ScaleRepository, backup *domain.ScaleBackup) error {
+	backup.Snapshot.Scales, _ = Migrate(backup.Snapshot.Scales)
+	problems := Validate(backup.Snapshot.Scales)
+	if len(problems) > 0 {
+		return &InvalidBackupError{Problems: problems}
+	}
+	return repo.Load(ctx, backup.Snapshot)
+}
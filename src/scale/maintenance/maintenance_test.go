@@ -0,0 +1,306 @@
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/scale/src/scale/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	helper.InitTime()
+}
+
+// fixtures needs the location set by init
+func fixtures() (date, deletedAt time.Time) {
+	date = time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	return date, date.Add(time.Hour)
+}
+
+func TestValidate(t *testing.T) {
+	date, deletedAt := fixtures()
+	tests := []struct {
+		name       string
+		args       []domain.Scale
+		wantResult []Problem
+	}{
+		{
+			name: "valid",
+			args: []domain.Scale{
+				{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1},
+				{Date: date, Min: 40, Max: 30, Difference: 0, Version: 1, DeletedAt: &deletedAt},
+			},
+			wantResult: []Problem{},
+		},
+		{
+			name: "max below min",
+			args: []domain.Scale{
+				{Date: date, Min: 50, Max: 45, Difference: -5, Version: 1},
+			},
+			wantResult: []Problem{
+				{Date: "2022-02-01", Version: 1, Reason: "max 45 is below min 50"},
+			},
+		},
+		{
+			name: "difference",
+			args: []domain.Scale{
+				{Date: date, Min: 45, Max: 50, Difference: 3, Version: 1},
+			},
+			wantResult: []Problem{
+				{Date: "2022-02-01", Version: 1, Reason: "difference is 3, want 5"},
+			},
+		},
+		{
+			name: "no version",
+			args: []domain.Scale{
+				{Date: date, Min: 45, Max: 50, Difference: 5},
+			},
+			wantResult: []Problem{
+				{Date: "2022-02-01", Reason: "no version, run the migrations"},
+			},
+		},
+		{
+			name: "same day",
+			args: []domain.Scale{
+				{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1},
+				{Date: date, Min: 46, Max: 50, Difference: 4, Version: 2},
+			},
+			wantResult: []Problem{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.wantResult, Validate(test.args))
+		})
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	date, deletedAt := fixtures()
+	got, removed := Dedupe([]domain.Scale{
+		{Date: date, Min: 45, Max: 50, Difference: 5, Version: 2},
+		{Date: date, Min: 46, Max: 50, Difference: 4, Version: 3},
+		{Date: date, Min: 47, Max: 50, Difference: 3, Version: 1, DeletedAt: &deletedAt},
+		{Date: date.AddDate(0, 0, 1), Min: 44, Max: 50, Difference: 6, Version: 1},
+		{Date: date.AddDate(0, 0, 1), Min: 43, Max: 50, Difference: 7, Version: 1},
+	})
+
+	assert.Equal(t, 2, removed)
+	assert.Equal(t, []domain.Scale{
+		{Date: date, Min: 46, Max: 50, Difference: 4, Version: 3},
+		{Date: date, Min: 47, Max: 50, Difference: 3, Version: 1, DeletedAt: &deletedAt},
+		{Date: date.AddDate(0, 0, 1), Min: 43, Max: 50, Difference: 7, Version: 1},
+	}, got)
+}
+
+func TestRecompute(t *testing.T) {
+	date, _ := fixtures()
+	got, changed := Recompute([]domain.Scale{
+		{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1},
+		{Date: date.AddDate(0, 0, 1), Min: 44, Max: 50, Difference: 0, Version: 1},
+	})
+
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, 6, got[1].Difference)
+}
+
+func TestMigrate(t *testing.T) {
+	date, deletedAt := fixtures()
+	got, changed := Migrate([]domain.Scale{
+		{Date: date, Min: 45, Max: 50, Difference: 5},
+		{Date: date, Min: 45, Max: 50, Difference: 5, Version: 3, DeletedAt: &deletedAt},
+		{Date: date.AddDate(0, 0, 1), Min: 44, Max: 50, Difference: 6},
+	})
+
+	assert.Equal(t, map[string]int{"0001_versions": 2}, changed)
+	assert.Equal(t, 4, got[0].Version)
+	assert.Equal(t, 1, got[2].Version)
+
+	_, changed = Migrate(got)
+	assert.Equal(t, map[string]int{"0001_versions": 0}, changed, "migrations run again change nothing")
+}
+
+func TestApply(t *testing.T) {
+	date, _ := fixtures()
+	ctx := context.Background()
+	scales := []domain.Scale{
+		{Date: date, Min: 45, Max: 50, Difference: 0, Version: 1},
+	}
+
+	tests := []struct {
+		name       string
+		dryRun     bool
+		wantResult int
+	}{
+		{
+			name:       "dry run",
+			dryRun:     true,
+			wantResult: 0,
+		},
+		{
+			name:       "write",
+			wantResult: 5,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := repository.NewScaleRepository()
+			repo.Load(ctx, &domain.ScaleSnapshot{Scales: scales})
+
+			changed, err := Apply(ctx, repo, test.dryRun, Recompute)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, changed)
+
+			got, _ := repo.Dump(ctx)
+			assert.Equal(t, test.wantResult, got.Scales[0].Difference)
+		})
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	date, deletedAt := fixtures()
+	ctx := context.Background()
+	snapshot := &domain.ScaleSnapshot{
+		Scales: []domain.Scale{
+			{Date: date, Min: 45, Max: 50, Difference: 5, Version: 2, DeletedAt: &deletedAt},
+			{Date: date.AddDate(0, 0, 1), Min: 44, Max: 50, Difference: 6},
+		},
+		History: []domain.ScaleHistory{
+			{Date: date, Action: domain.ScaleDeleted, Actor: "budi", ChangedAt: deletedAt},
+		},
+	}
+	history := repository.NewScaleHistoryRepository()
+	source := repository.NewAuditScaleRepository(repository.NewScaleRepository(), history)
+	source.Load(ctx, snapshot)
+
+	backup, err := Backup(ctx, source)
+	if !assert.NoError(t, err) {
+		return
+	}
+	buf := &bytes.Buffer{}
+	err = WriteBackup(buf, backup)
+	if !assert.NoError(t, err) {
+		return
+	}
+	backup, err = ReadBackup(buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	target := repository.NewScaleRepository()
+	target.Create(ctx, &domain.Scale{Date: date, Min: 1, Max: 2})
+	err = Restore(ctx, target, backup)
+	assert.NoError(t, err)
+
+	got, _ := target.Dump(ctx)
+	if assert.Len(t, got.Scales, 2) {
+		assert.True(t, got.Scales[0].Date.Equal(date))
+		assert.True(t, got.Scales[0].DeletedAt.Equal(deletedAt))
+		assert.Equal(t, 2, got.Scales[0].Version)
+		assert.Equal(t, 1, got.Scales[1].Version, "restore migrates")
+	}
+	assert.Len(t, backup.Snapshot.History, 1)
+}
+
+func TestRestoreSameDay(t *testing.T) {
+	date, _ := fixtures()
+	ctx := context.Background()
+	source := repository.NewScaleRepository()
+	source.Create(ctx, &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1})
+	source.Create(ctx, &domain.Scale{Date: date, Min: 46, Max: 50, Difference: 4, Version: 1})
+	backup, err := Backup(ctx, source)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	target := repository.NewScaleRepository()
+	assert.NoError(t, Restore(ctx, target, backup))
+	got, _ := target.Dump(ctx)
+	assert.Len(t, got.Scales, 2)
+}
+
+func TestRestoreInvalid(t *testing.T) {
+	date, _ := fixtures()
+	repo := repository.NewScaleRepository()
+	backup, _ := NewBackup(&domain.ScaleSnapshot{
+		Scales: []domain.Scale{
+			{Date: date, Min: 50, Max: 45, Difference: -5, Version: 1},
+		},
+	})
+
+	err := Restore(context.Background(), repo, backup)
+	assert.Equal(t, &InvalidBackupError{Problems: []Problem{
+		{Date: "2022-02-01", Version: 1, Reason: "max 45 is below min 50"},
+	}}, err)
+}
+
+func TestReadBackup(t *testing.T) {
+	date, _ := fixtures()
+	backup, _ := NewBackup(&domain.ScaleSnapshot{
+		Scales: []domain.Scale{
+			{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1},
+		},
+	})
+	buf := &bytes.Buffer{}
+	WriteBackup(buf, backup)
+	current := buf.String()
+
+	tests := []struct {
+		name       string
+		args       string
+		wantScales int
+		wantErr    string
+	}{
+		{
+			name:       "current",
+			args:       current,
+			wantScales: 1,
+		},
+		{
+			name:       "version 1",
+			args:       `{"version":1,"created_at":"2022-02-01T07:00:00+07:00","scales":[{"date":"2022-02-01T00:00:00+07:00","min":45,"max":50,"difference":5,"version":1}]}`,
+			wantScales: 1,
+		},
+		{
+			name:    "tampered",
+			args:    strings.Replace(current, `"min": 45`, `"min": 40`, 1),
+			wantErr: "backup checksum is " + backup.Checksum + ", want ",
+		},
+		{
+			name:    "no snapshot",
+			args:    `{"version":2,"created_at":"2022-02-01T07:00:00+07:00","checksum":""}`,
+			wantErr: "backup has no snapshot",
+		},
+		{
+			name:    "unknown version",
+			args:    `{"version":3,"created_at":"2022-02-01T07:00:00+07:00"}`,
+			wantErr: "backup version 3 is not supported, want at most 2",
+		},
+		{
+			name:    "unknown field",
+			args:    `{"version":2,"rows":[]}`,
+			wantErr: `json: unknown field "rows"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ReadBackup(strings.NewReader(test.args))
+			if test.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.wantErr)
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, domain.ScaleBackupVersion, got.Version)
+				assert.Len(t, got.Snapshot.Scales, test.wantScales)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/sirupsen/logrus"
+)
+
+type auditScaleRepository struct {
+	domain.ScaleRepository
+	historyRepository domain.ScaleHistoryRepository
+	// keeps the before and after readings of a write consistent with it
+	mu sync.Mutex
+}
+
+// NewAuditScaleRepository wraps any ScaleRepository, appending a ScaleHistory
+// entry for every successful write. Load replaces the history along with the
+// readings instead.
+func NewAuditScaleRepository(scaleRepository domain.ScaleRepository, historyRepository domain.ScaleHistoryRepository) domain.ScaleRepository {
+	return &auditScaleRepository{
+		ScaleRepository:   scaleRepository,
+		historyRepository: historyRepository,
+	}
+}
+
+func (a *auditScaleRepository) audit(ctx context.Context, action string, date time.Time, write func() error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	before, err := a.ScaleRepository.GetScale(ctx, date)
+	if err != nil {
+		return err
+	}
+	// the backend may hand out its own slice
+	before = append([]domain.Scale{}, before...)
+
+	err = write()
+	if err != nil {
+		return err
+	}
+
+	// the write is kept from here on, failing would have the caller retry it
+	after, err := a.ScaleRepository.GetScale(ctx, date)
+	if err != nil {
+		historyNotRecorded(ctx, action, date, err)
+		return nil
+	}
+	a.record(ctx, &domain.ScaleHistory{
+		Date:      date,
+		Action:    action,
+		Actor:     helper.GetActor(ctx),
+		Before:    before,
+		After:     append([]domain.Scale{}, after...),
+		ChangedAt: helper.Now(),
+	})
+	return nil
+}
+
+// record appends history of a write already kept, only logging a failure
+// since the write cannot be taken back anymore.
+func (a *auditScaleRepository) record(ctx context.Context, history *domain.ScaleHistory) {
+	err := a.historyRepository.Create(history)
+	if err != nil {
+		historyNotRecorded(ctx, history.Action, history.Date, err)
+	}
+}
+
+func historyNotRecorded(ctx context.Context, action string, date time.Time, err error) {
+	helper.GetLogger(ctx).WithFields(logrus.Fields{
+		"action": action,
+		"date":   date.Format(common.TimeLayout),
+	}).WithError(err).Error("history not recorded")
+}
+
+func (a *auditScaleRepository) Create(ctx context.Context, param *domain.Scale) error {
+	return a.audit(ctx, domain.ScaleCreated, param.Date, func() error {
+		return a.ScaleRepository.Create(ctx, param)
+	})
+}
+
+func (a *auditScaleRepository) Update(ctx context.Context, param *domain.Scale) error {
+	return a.audit(ctx, domain.ScaleUpdated, param.Date, func() error {
+		return a.ScaleRepository.Update(ctx, param)
+	})
+}
+
+func (a *auditScaleRepository) Delete(ctx context.Context, date time.Time, version int) error {
+	return a.audit(ctx, domain.ScaleDeleted, date, func() error {
+		return a.ScaleRepository.Delete(ctx, date, version)
+	})
+}
+
+func (a *auditScaleRepository) Restore(ctx context.Context, date time.Time) error {
+	return a.audit(ctx, domain.ScaleRestored, date, func() error {
+		return a.ScaleRepository.Restore(ctx, date)
+	})
+}
+
+// Dump adds the history to the snapshot of the readings, waiting for the
+// write in progress so both agree.
+func (a *auditScaleRepository) Dump(ctx context.Context) (*domain.ScaleSnapshot, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	snapshot, err := a.ScaleRepository.Dump(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.History, err = a.historyRepository.Dump()
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func (a *auditScaleRepository) Load(ctx context.Context, snapshot *domain.ScaleSnapshot) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	err := a.ScaleRepository.Load(ctx, snapshot)
+	if err != nil {
+		return err
+	}
+	// backups from before the history was kept have none to replace it with
+	if snapshot.History == nil {
+		return nil
+	}
+	return a.historyRepository.Load(snapshot.History)
+}
+
+// Transaction records the history of the writes of fn once they are kept.
+func (a *auditScaleRepository) Transaction(ctx context.Context, fn func(tx domain.ScaleRepository) error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	history := &scaleHistoryRepository{}
+	err := a.ScaleRepository.Transaction(ctx, func(tx domain.ScaleRepository) error {
+		return fn(NewAuditScaleRepository(tx, history))
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range history.history {
+		a.record(ctx, &history.history[i])
+	}
+	return nil
+}
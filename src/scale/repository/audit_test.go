@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudit(t *testing.T) {
+	now := time.Date(2022, 2, 1, 7, 0, 0, 0, helper.GetLocation())
+	original := helper.Now
+	helper.Now = func() time.Time { return now }
+	defer func() { helper.Now = original }()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	history := NewScaleHistoryRepository()
+	repo := NewAuditScaleRepository(NewScaleRepository(), history)
+	ctx := helper.WithActor(context.Background(), "budi")
+
+	assert.NoError(t, repo.Create(ctx, &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5}))
+	assert.NoError(t, repo.Update(ctx, &domain.Scale{Date: date, Min: 46, Max: 50}))
+	assert.NoError(t, repo.Delete(ctx, date, 0))
+	assert.NoError(t, repo.Restore(context.Background(), date))
+
+	got, err := history.GetHistory(date)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ScaleHistory{
+		{
+			Date:      date,
+			Action:    domain.ScaleCreated,
+			Actor:     "budi",
+			Before:    []domain.Scale{},
+			After:     []domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1}},
+			ChangedAt: now,
+		},
+		{
+			Date:      date,
+			Action:    domain.ScaleUpdated,
+			Actor:     "budi",
+			Before:    []domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1}},
+			After:     []domain.Scale{{Date: date, Min: 46, Max: 50, Difference: 4, Version: 2}},
+			ChangedAt: now,
+		},
+		{
+			Date:      date,
+			Action:    domain.ScaleDeleted,
+			Actor:     "budi",
+			Before:    []domain.Scale{{Date: date, Min: 46, Max: 50, Difference: 4, Version: 2}},
+			After:     []domain.Scale{},
+			ChangedAt: now,
+		},
+		{
+			Date:      date,
+			Action:    domain.ScaleRestored,
+			Before:    []domain.Scale{},
+			After:     []domain.Scale{{Date: date, Min: 46, Max: 50, Difference: 4, Version: 4}},
+			ChangedAt: now,
+		},
+	}, got)
+}
+
+func TestAuditTransaction(t *testing.T) {
+	now := time.Date(2022, 2, 1, 7, 0, 0, 0, helper.GetLocation())
+	original := helper.Now
+	helper.Now = func() time.Time { return now }
+	defer func() { helper.Now = original }()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	history := NewScaleHistoryRepository()
+	repo := NewAuditScaleRepository(NewScaleRepository(), history)
+
+	err := repo.Transaction(context.Background(), func(tx domain.ScaleRepository) error {
+		err := tx.Create(context.Background(), &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5})
+		if err != nil {
+			return err
+		}
+		return tx.Delete(context.Background(), date, 2)
+	})
+	assert.Equal(t, domain.ErrPreconditionFailed, err)
+
+	got, err := history.GetHistory(date)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+
+	err = repo.Transaction(context.Background(), func(tx domain.ScaleRepository) error {
+		return tx.Create(context.Background(), &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5})
+	})
+	assert.NoError(t, err)
+
+	got, err = history.GetHistory(date)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ScaleHistory{
+		{
+			Date:      date,
+			Action:    domain.ScaleCreated,
+			Before:    []domain.Scale{},
+			After:     []domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1}},
+			ChangedAt: now,
+		},
+	}, got)
+}
+
+func TestAuditError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	scaleMock := mock_domain.NewMockScaleRepository(ctrl)
+	historyMock := mock_domain.NewMockScaleHistoryRepository(ctrl)
+	repo := NewAuditScaleRepository(scaleMock, historyMock)
+
+	tests := []struct {
+		name    string
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name:    "write error",
+			wantErr: true,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), date).Return([]domain.Scale{}, nil)
+				scaleMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+			},
+		},
+		{
+			name:    "history error",
+			wantErr: false,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), date).Return([]domain.Scale{}, nil).Times(2)
+				scaleMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+				historyMock.EXPECT().Create(gomock.Any()).Return(errors.New("some error"))
+			},
+		},
+		{
+			name:    "read after write error",
+			wantErr: false,
+			mock: func() {
+				scaleMock.EXPECT().GetScale(gomock.Any(), date).Return([]domain.Scale{}, nil)
+				scaleMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+				scaleMock.EXPECT().GetScale(gomock.Any(), date).Return(nil, errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+
+			err := repo.Update(context.Background(), &domain.Scale{Date: date, Min: 46, Max: 50})
+			assert.Equal(t, test.wantErr, err != nil)
+		})
+	}
+}
+
+func TestAuditTransactionHistoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	historyMock := mock_domain.NewMockScaleHistoryRepository(ctrl)
+	scales := NewScaleRepository()
+	repo := NewAuditScaleRepository(scales, historyMock)
+
+	historyMock.EXPECT().Create(gomock.Any()).Return(errors.New("some error")).Times(2)
+	err := repo.Transaction(context.Background(), func(tx domain.ScaleRepository) error {
+		err := tx.Create(context.Background(), &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5})
+		if err != nil {
+			return err
+		}
+		return tx.Create(context.Background(), &domain.Scale{Date: date.AddDate(0, 0, 1), Min: 45, Max: 50, Difference: 5})
+	})
+	assert.NoError(t, err, "the writes are kept")
+
+	got, err := scales.GetScales(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+}
+
+func TestAuditDumpLoad(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	source := NewAuditScaleRepository(NewScaleRepository(), NewScaleHistoryRepository())
+	err := source.Create(context.Background(), &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5})
+	assert.NoError(t, err)
+
+	snapshot, err := source.Dump(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, snapshot.Scales, 1)
+	if assert.Len(t, snapshot.History, 1) {
+		assert.Equal(t, domain.ScaleCreated, snapshot.History[0].Action)
+	}
+
+	history := NewScaleHistoryRepository()
+	history.Create(&domain.ScaleHistory{Date: date.AddDate(0, 0, 1), Action: domain.ScaleCreated})
+	target := NewAuditScaleRepository(NewScaleRepository(), history)
+	err = target.Load(context.Background(), snapshot)
+	assert.NoError(t, err)
+
+	got, err := target.Dump(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot, got)
+}
+
+func TestAuditLoadWithoutHistory(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	history := NewScaleHistoryRepository()
+	history.Create(&domain.ScaleHistory{Date: date, Action: domain.ScaleCreated})
+	repo := NewAuditScaleRepository(NewScaleRepository(), history)
+
+	// as read from a version 1 backup
+	err := repo.Load(context.Background(), &domain.ScaleSnapshot{
+		Scales: []domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5}},
+	})
+	assert.NoError(t, err)
+
+	got, err := repo.Dump(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, got.Scales, 1)
+	if assert.Len(t, got.History, 1) {
+		assert.Equal(t, domain.ScaleCreated, got.History[0].Action)
+	}
+
+	err = repo.Load(context.Background(), &domain.ScaleSnapshot{History: []domain.ScaleHistory{}})
+	assert.NoError(t, err)
+	got, err = repo.Dump(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, got.History)
+}
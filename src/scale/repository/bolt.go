@@ -0,0 +1,424 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+var scalesBucket = []byte("scales")
+
+// boltOpenTimeout bounds the wait for the file lock another process holds
+const boltOpenTimeout = time.Second
+
+// boltScaleRepository keeps every reading of a day, deleted ones included,
+// under the day as key, so cursors walk the readings by date without sorting.
+// Transactions and Dump get isolation from bbolt itself, reads never wait for
+// writes.
+type boltScaleRepository struct {
+	db     *bolt.DB
+	logger logrus.FieldLogger
+	// set inside Transaction, every call then joins it
+	tx *bolt.Tx
+}
+
+// NewBoltScaleRepository opens the bbolt database at path, creating it when
+// missing.
+func NewBoltScaleRepository(path string, logger logrus.FieldLogger) (domain.ScaleRepository, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(scalesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltScaleRepository{db: db, logger: logger}, nil
+}
+
+func (b *boltScaleRepository) view(ctx context.Context, fn func(bucket *bolt.Bucket) error) error {
+	if b.tx != nil {
+		return inBucket(ctx, b.tx, fn)
+	}
+	return b.db.View(func(tx *bolt.Tx) error {
+		return inBucket(ctx, tx, fn)
+	})
+}
+
+func (b *boltScaleRepository) update(ctx context.Context, fn func(bucket *bolt.Bucket) error) error {
+	if b.tx != nil {
+		return inBucket(ctx, b.tx, fn)
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return inBucket(ctx, tx, fn)
+	})
+}
+
+// inBucket checks ctx once the transaction began, for writes once they hold the
+// lock, like the memory repository.
+func inBucket(ctx context.Context, tx *bolt.Tx, fn func(bucket *bolt.Bucket) error) error {
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+	return fn(tx.Bucket(scalesBucket))
+}
+
+func dayKey(date time.Time) []byte {
+	return []byte(date.Format(common.TimeLayout))
+}
+
+// getDay decodes the readings of key. The value belongs to the transaction,
+// so they are copied out of it by decoding.
+func getDay(bucket *bolt.Bucket, key []byte) ([]domain.Scale, error) {
+	scales := []domain.Scale{}
+	value := bucket.Get(key)
+	if value == nil {
+		return scales, nil
+	}
+	err := json.Unmarshal(value, &scales)
+	return scales, err
+}
+
+func putDay(bucket *bolt.Bucket, key []byte, scales []domain.Scale) error {
+	if len(scales) == 0 {
+		return bucket.Delete(key)
+	}
+	value, err := json.Marshal(scales)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, value)
+}
+
+// dayVersions is scaleRepository.versions for the readings of one day.
+func dayVersions(scales []domain.Scale) (current, last int) {
+	for _, scale := range scales {
+		if scale.Version > last {
+			last = scale.Version
+		}
+		if scale.DeletedAt == nil && scale.Version > current {
+			current = scale.Version
+		}
+	}
+	return current, last
+}
+
+// newestFirst orders the readings of a day like the memory repository, days
+// themselves come ordered from the cursor.
+func newestFirst(scales []domain.Scale) {
+	sort.SliceStable(scales, func(i, j int) bool {
+		return scales[i].Date.After(scales[j].Date)
+	})
+}
+
+func (b *boltScaleRepository) Create(ctx context.Context, param *domain.Scale) error {
+	return b.update(ctx, func(bucket *bolt.Bucket) error {
+		key := dayKey(param.Date)
+		scales, err := getDay(bucket, key)
+		if err != nil {
+			return err
+		}
+
+		_, last := dayVersions(scales)
+		param.Version = last + 1
+		return putDay(bucket, key, append(scales, *param))
+	})
+}
+
+func (b *boltScaleRepository) GetScales(ctx context.Context) ([]domain.Scale, error) {
+	scaleResponse := []domain.Scale{}
+	err := b.view(ctx, func(bucket *bolt.Bucket) error {
+		cursor := bucket.Cursor()
+		for key, _ := cursor.Last(); key != nil; key, _ = cursor.Prev() {
+			scales, err := getDay(bucket, key)
+			if err != nil {
+				return err
+			}
+			newestFirst(scales)
+			for _, scale := range scales {
+				if scale.DeletedAt == nil {
+					scaleResponse = append(scaleResponse, scale)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scaleResponse, nil
+}
+
+func (b *boltScaleRepository) GetScale(ctx context.Context, date time.Time) ([]domain.Scale, error) {
+	scaleResponse := []domain.Scale{}
+	err := b.view(ctx, func(bucket *bolt.Bucket) error {
+		scales, err := getDay(bucket, dayKey(date))
+		if err != nil {
+			return err
+		}
+		newestFirst(scales)
+		for _, scale := range scales {
+			if scale.DeletedAt == nil {
+				scaleResponse = append(scaleResponse, scale)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scaleResponse, nil
+}
+
+func (b *boltScaleRepository) Update(ctx context.Context, param *domain.Scale) error {
+	return b.update(ctx, func(bucket *bolt.Bucket) error {
+		key := dayKey(param.Date)
+		scales, err := getDay(bucket, key)
+		if err != nil {
+			return err
+		}
+
+		current, last := dayVersions(scales)
+		if param.Version != 0 && param.Version != current {
+			return domain.ErrPreconditionFailed
+		}
+
+		for i, scale := range scales {
+			if scale.DeletedAt == nil {
+				scales[i].Min = param.Min
+				scales[i].Max = param.Max
+				scales[i].Difference = param.Max - param.Min
+				scales[i].Version = last + 1
+				param.Version = last + 1
+			}
+		}
+		return putDay(bucket, key, scales)
+	})
+}
+
+func (b *boltScaleRepository) Delete(ctx context.Context, date time.Time, version int) error {
+	return b.update(ctx, func(bucket *bolt.Bucket) error {
+		key := dayKey(date)
+		scales, err := getDay(bucket, key)
+		if err != nil {
+			return err
+		}
+
+		current, last := dayVersions(scales)
+		if version != 0 && version != current {
+			return domain.ErrPreconditionFailed
+		}
+
+		deletedAt := helper.Now()
+		for i, scale := range scales {
+			if scale.DeletedAt == nil {
+				scales[i].DeletedAt = &deletedAt
+				scales[i].Version = last + 1
+			}
+		}
+		return putDay(bucket, key, scales)
+	})
+}
+
+// GetTrash walks every day, the trash is ordered by deletion rather than date.
+func (b *boltScaleRepository) GetTrash(ctx context.Context) ([]domain.Scale, error) {
+	scaleResponse := []domain.Scale{}
+	err := b.view(ctx, func(bucket *bolt.Bucket) error {
+		return bucket.ForEach(func(key, _ []byte) error {
+			scales, err := getDay(bucket, key)
+			if err != nil {
+				return err
+			}
+			for _, scale := range scales {
+				if scale.DeletedAt != nil {
+					scaleResponse = append(scaleResponse, scale)
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(scaleResponse, func(i, j int) bool {
+		return scaleResponse[i].DeletedAt.After(*scaleResponse[j].DeletedAt)
+	})
+	return scaleResponse, nil
+}
+
+func (b *boltScaleRepository) Restore(ctx context.Context, date time.Time) error {
+	return b.update(ctx, func(bucket *bolt.Bucket) error {
+		key := dayKey(date)
+		scales, err := getDay(bucket, key)
+		if err != nil {
+			return err
+		}
+
+		_, last := dayVersions(scales)
+		deleted, live := []int{}, false
+		for i, scale := range scales {
+			if scale.DeletedAt != nil {
+				deleted = append(deleted, i)
+			} else {
+				live = true
+			}
+		}
+		if len(deleted) == 0 {
+			return domain.ErrNotFound
+		}
+		if live {
+			return domain.ErrConflict
+		}
+
+		for _, i := range deleted {
+			scales[i].DeletedAt = nil
+			scales[i].Version = last + 1
+		}
+		return putDay(bucket, key, scales)
+	})
+}
+
+func (b *boltScaleRepository) Purge(ctx context.Context, before time.Time) error {
+	return b.update(ctx, func(bucket *bolt.Bucket) error {
+		// bbolt does not allow writes while iterating with ForEach
+		days := map[string][]domain.Scale{}
+		err := bucket.ForEach(func(key, _ []byte) error {
+			scales, err := getDay(bucket, key)
+			if err != nil {
+				return err
+			}
+			kept := scales[:0]
+			for _, scale := range scales {
+				if scale.DeletedAt == nil || !scale.DeletedAt.Before(before) {
+					kept = append(kept, scale)
+				}
+			}
+			if len(kept) != len(scales) {
+				days[string(key)] = kept
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for day, scales := range days {
+			err = putDay(bucket, []byte(day), scales)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Transaction runs fn in a bbolt write transaction, rolled back when fn
+// fails. bbolt has no savepoints, a Transaction inside fn writes to a copy in
+// memory that replaces the readings only once it succeeds, so a failed one
+// leaves nothing behind as with the other storages.
+func (b *boltScaleRepository) Transaction(ctx context.Context, fn func(tx domain.ScaleRepository) error) error {
+	if b.tx != nil {
+		snapshot, err := b.Dump(ctx)
+		if err != nil {
+			return err
+		}
+		savepoint := &scaleRepository{scales: snapshot.Scales}
+		err = fn(savepoint)
+		if err != nil {
+			helper.GetLoggerOr(ctx, b.logger).WithError(err).Debug("nested transaction rolled back")
+			return err
+		}
+		return b.Load(ctx, &domain.ScaleSnapshot{Scales: savepoint.scales})
+	}
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+		return fn(&boltScaleRepository{db: b.db, logger: b.logger, tx: tx})
+	})
+	if err != nil {
+		helper.GetLoggerOr(ctx, b.logger).WithError(err).Debug("transaction rolled back")
+		return err
+	}
+	return nil
+}
+
+// Close releases the database and its file lock.
+func (b *boltScaleRepository) Close() error {
+	return b.db.Close()
+}
+
+// Health reports whether the database is still open and answers reads.
+func (b *boltScaleRepository) Health(ctx context.Context) error {
+	return b.view(ctx, func(bucket *bolt.Bucket) error {
+		return nil
+	})
+}
+
+// Dump reads every day within one read transaction, a consistent snapshot
+// whatever is written meanwhile.
+func (b *boltScaleRepository) Dump(ctx context.Context) (*domain.ScaleSnapshot, error) {
+	snapshot := &domain.ScaleSnapshot{Scales: []domain.Scale{}}
+	err := b.view(ctx, func(bucket *bolt.Bucket) error {
+		return bucket.ForEach(func(key, _ []byte) error {
+			scales, err := getDay(bucket, key)
+			if err != nil {
+				return err
+			}
+			snapshot.Scales = append(snapshot.Scales, scales...)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func (b *boltScaleRepository) Load(ctx context.Context, snapshot *domain.ScaleSnapshot) error {
+	days := map[string][]domain.Scale{}
+	for _, scale := range snapshot.Scales {
+		day := string(dayKey(scale.Date))
+		days[day] = append(days[day], scale)
+	}
+
+	return b.update(ctx, func(bucket *bolt.Bucket) error {
+		// emptied key by key, the bucket of a joined transaction must stay
+		keys := [][]byte{}
+		err := bucket.ForEach(func(key, _ []byte) error {
+			keys = append(keys, append([]byte{}, key...))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			err = bucket.Delete(key)
+			if err != nil {
+				return err
+			}
+		}
+
+		for day, scales := range days {
+			err = putDay(bucket, []byte(day), scales)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
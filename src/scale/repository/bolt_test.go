@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoltReopen(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	path := filepath.Join(t.TempDir(), "scales.db")
+	ctx := context.Background()
+	repo, err := NewBoltScaleRepository(path, logrus.New())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, repo.Create(ctx, &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5}))
+	want := dumpScales(t, repo)
+
+	// the file is locked while open
+	_, err = NewBoltScaleRepository(path, logrus.New())
+	assert.Error(t, err)
+
+	assert.NoError(t, repo.(*boltScaleRepository).Close())
+	reopened, err := NewBoltScaleRepository(path, logrus.New())
+	if assert.NoError(t, err) {
+		defer reopened.(*boltScaleRepository).Close()
+		assertScales(t, want, dumpScales(t, reopened))
+	}
+}
+
+func TestBoltNestedTransaction(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	ctx := context.Background()
+	repo, err := NewBoltScaleRepository(filepath.Join(t.TempDir(), "scales.db"), logrus.New())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer repo.(*boltScaleRepository).Close()
+
+	err = repo.Transaction(ctx, func(tx domain.ScaleRepository) error {
+		err := tx.Transaction(ctx, func(inner domain.ScaleRepository) error {
+			return inner.Create(ctx, &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5})
+		})
+		if err != nil {
+			return err
+		}
+		return errors.New("rolled back")
+	})
+	assert.EqualError(t, err, "rolled back")
+	assert.Empty(t, dumpScales(t, repo), "the inner writes go with the outer transaction")
+}
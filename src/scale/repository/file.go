@@ -0,0 +1,452 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/sirupsen/logrus"
+)
+
+// the log is rewritten once it holds this many records
+const fileCompactRecords = 1000
+
+// errLocked is returned by lockFile when another process holds the lock.
+var errLocked = errors.New("file is locked")
+
+// fileRecord is one write in the log. Replaying it clears every reading when
+// Reset, purges the deleted ones older than Purge, then replaces the readings
+// of every day of Days, deleted ones included, with the given ones.
+type fileRecord struct {
+	Reset bool                      `json:"reset,omitempty"`
+	Purge *time.Time                `json:"purge,omitempty"`
+	Days  map[string][]domain.Scale `json:"days,omitempty"`
+}
+
+// logFile is the part of *os.File the log is written with, tests swap in
+// failing ones.
+type logFile interface {
+	io.ReadWriteCloser
+	Sync() error
+	Truncate(size int64) error
+}
+
+// fileScaleRepository keeps the readings in memory and every write as a line
+// of JSON appended to an append-only log, synced before the write returns so
+// the process may stop at any point. Reads never touch the file.
+type fileScaleRepository struct {
+	*scaleRepository
+	path   string
+	logger logrus.FieldLogger
+	// held with an exclusive lock while open, not the log itself which
+	// compactions replace
+	lock *os.File
+
+	// keeps the log in the order of the writes
+	mu           sync.Mutex
+	file         logFile
+	size         int64
+	records      int
+	compactAfter int
+	// set when a failed write could not be taken back, the log may end in
+	// a partial record and every later write is refused
+	failed error
+}
+
+// NewFileScaleRepository replays the log at path, creating it when missing.
+// A last record cut short by a crash is dropped, any other broken record
+// fails. Only one process may have the log open, path.lock tells.
+func NewFileScaleRepository(path string, logger logrus.FieldLogger) (domain.ScaleRepository, error) {
+	lock, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	err = lockFile(lock)
+	if err != nil {
+		lock.Close()
+		if err == errLocked {
+			return nil, fmt.Errorf("%s is in use by another process", path)
+		}
+		return nil, err
+	}
+
+	f, err := openFile(path, logger)
+	if err != nil {
+		lock.Close()
+		return nil, err
+	}
+	f.lock = lock
+	return f, nil
+}
+
+func openFile(path string, logger logrus.FieldLogger) (*fileScaleRepository, error) {
+	// left over by a compaction that did not finish, the log is still whole
+	err := os.Remove(path + ".tmp")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	f := &fileScaleRepository{
+		scaleRepository: &scaleRepository{},
+		path:            path,
+		logger:          logger,
+		file:            file,
+		compactAfter:    fileCompactRecords,
+	}
+	err = f.replay()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if f.records > f.compactAfter {
+		err = f.compact(f.scales)
+		if err != nil {
+			f.file.Close()
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Health fails once a write could not be taken back, every write is refused
+// from then on.
+func (f *fileScaleRepository) Health(ctx context.Context) error {
+	f.mu.Lock()
+	failed := f.failed
+	f.mu.Unlock()
+
+	if failed != nil {
+		return failed
+	}
+	return f.scaleRepository.Health(ctx)
+}
+
+// Close releases the log and its lock.
+func (f *fileScaleRepository) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	err := f.file.Close()
+	// closing drops the lock
+	lockErr := f.lock.Close()
+	if err == nil {
+		err = lockErr
+	}
+	return err
+}
+
+func (f *fileScaleRepository) replay() error {
+	reader := bufio.NewReader(f.file)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF && len(line) == 0 {
+			f.size = offset
+			return nil
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		record := fileRecord{}
+		decodeErr := json.Unmarshal(line, &record)
+		if err == io.EOF || decodeErr != nil {
+			// only the last record can be torn by a crash
+			_, peekErr := reader.Peek(1)
+			if peekErr != io.EOF {
+				return fmt.Errorf("%s: broken record at offset %d: %v", f.path, offset, decodeErr)
+			}
+			f.logger.WithFields(logrus.Fields{
+				"path":   f.path,
+				"offset": offset,
+			}).Warn("dropping incomplete last record")
+			f.size = offset
+			return f.file.Truncate(offset)
+		}
+
+		f.scaleRepository.apply(&record)
+		f.records++
+		offset += int64(len(line))
+	}
+}
+
+// apply replays record, the caller holds the lock or owns s.
+func (s *scaleRepository) apply(record *fileRecord) {
+	if record.Reset {
+		s.scales = nil
+	}
+	if record.Purge != nil {
+		scales := s.scales[:0]
+		for _, scale := range s.scales {
+			if scale.DeletedAt == nil || !scale.DeletedAt.Before(*record.Purge) {
+				scales = append(scales, scale)
+			}
+		}
+		s.scales = scales
+	}
+	for day, readings := range record.Days {
+		scales := s.scales[:0]
+		for _, scale := range s.scales {
+			if scale.Date.Format(common.TimeLayout) != day {
+				scales = append(scales, scale)
+			}
+		}
+		s.scales = append(scales, readings...)
+	}
+}
+
+// days groups the readings of s, deleted ones included, by day. Only the given
+// days are kept unless all.
+func (s *scaleRepository) days(days map[string]bool, all bool) map[string][]domain.Scale {
+	grouped := map[string][]domain.Scale{}
+	for day := range days {
+		grouped[day] = []domain.Scale{}
+	}
+	for _, scale := range s.scales {
+		day := scale.Date.Format(common.TimeLayout)
+		if all || days[day] {
+			grouped[day] = append(grouped[day], scale)
+		}
+	}
+	return grouped
+}
+
+// write runs fn in a transaction of the memory store, keeping its writes only
+// once they are in the log.
+func (f *fileScaleRepository) write(ctx context.Context, fn func(tx domain.ScaleRepository) error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failed != nil {
+		return f.failed
+	}
+	err := f.scaleRepository.Transaction(ctx, func(tx domain.ScaleRepository) error {
+		recorder := &fileRecorder{ScaleRepository: tx, days: map[string]bool{}}
+		err := fn(recorder)
+		if err != nil {
+			return err
+		}
+
+		state := tx.(*scaleRepository)
+		if recorder.reset {
+			return f.compact(state.scales)
+		}
+		if len(recorder.days) == 0 && recorder.purge == nil {
+			return nil
+		}
+		return f.append(&fileRecord{
+			Purge: recorder.purge,
+			Days:  state.days(recorder.days, false),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if f.records > f.compactAfter {
+		f.scaleRepository.mu.RLock()
+		err = f.compact(f.scaleRepository.scales)
+		f.scaleRepository.mu.RUnlock()
+		if err != nil {
+			// the log is still whole, only longer than it should be
+			helper.GetLoggerOr(ctx, f.logger).WithError(err).Error("log not compacted")
+		}
+	}
+	return nil
+}
+
+// append writes record at the end of the log. On failure the log is cut back
+// to where it ended, a partial record would otherwise sit between the next
+// ones and fail the replay.
+func (f *fileScaleRepository) append(record *fileRecord) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line := append(b, '\n')
+	_, err = f.file.Write(line)
+	if err == nil {
+		err = f.file.Sync()
+	}
+	if err != nil {
+		truncateErr := f.file.Truncate(f.size)
+		if truncateErr != nil {
+			f.failed = fmt.Errorf("%s: log unusable after a failed write: %v", f.path, truncateErr)
+		}
+		return err
+	}
+	f.size += int64(len(line))
+	f.records++
+	return nil
+}
+
+// compact rewrites the log as a single record of scales, swapping it in with
+// a rename so a crash leaves either the old log or the new one.
+func (f *fileScaleRepository) compact(scales []domain.Scale) error {
+	state := &scaleRepository{scales: scales}
+	b, err := json.Marshal(&fileRecord{Reset: true, Days: state.days(nil, true)})
+	if err != nil {
+		return err
+	}
+
+	tmp := f.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(b, '\n'))
+	if err == nil {
+		err = file.Sync()
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+	err = os.Rename(tmp, f.path)
+	if err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+	f.file.Close()
+	f.file = file
+	f.size = int64(len(b) + 1)
+	f.records = 1
+
+	// the rename is only durable once the directory is synced, but it is
+	// done, failing now would leave the caller thinking the old log is kept
+	err = syncDir(filepath.Dir(f.path))
+	if err != nil {
+		f.logger.WithField("path", f.path).WithError(err).Warn("log directory not synced")
+	}
+	return nil
+}
+
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
+func (f *fileScaleRepository) Create(ctx context.Context, param *domain.Scale) error {
+	return f.write(ctx, func(tx domain.ScaleRepository) error {
+		return tx.Create(ctx, param)
+	})
+}
+
+func (f *fileScaleRepository) Update(ctx context.Context, param *domain.Scale) error {
+	return f.write(ctx, func(tx domain.ScaleRepository) error {
+		return tx.Update(ctx, param)
+	})
+}
+
+func (f *fileScaleRepository) Delete(ctx context.Context, date time.Time, version int) error {
+	return f.write(ctx, func(tx domain.ScaleRepository) error {
+		return tx.Delete(ctx, date, version)
+	})
+}
+
+func (f *fileScaleRepository) Restore(ctx context.Context, date time.Time) error {
+	return f.write(ctx, func(tx domain.ScaleRepository) error {
+		return tx.Restore(ctx, date)
+	})
+}
+
+func (f *fileScaleRepository) Purge(ctx context.Context, before time.Time) error {
+	return f.write(ctx, func(tx domain.ScaleRepository) error {
+		return tx.Purge(ctx, before)
+	})
+}
+
+// Transaction logs the writes of fn as a single record, so a crash keeps all
+// of them or none.
+func (f *fileScaleRepository) Transaction(ctx context.Context, fn func(tx domain.ScaleRepository) error) error {
+	return f.write(ctx, fn)
+}
+
+// Load rewrites the log rather than appending everything to it.
+func (f *fileScaleRepository) Load(ctx context.Context, snapshot *domain.ScaleSnapshot) error {
+	return f.write(ctx, func(tx domain.ScaleRepository) error {
+		return tx.Load(ctx, snapshot)
+	})
+}
+
+// fileRecorder notes what the writes of a transaction touched, the record is
+// then made of how they left it.
+type fileRecorder struct {
+	domain.ScaleRepository
+	days  map[string]bool
+	purge *time.Time
+	reset bool
+}
+
+func (r *fileRecorder) touch(date time.Time) {
+	r.days[date.Format(common.TimeLayout)] = true
+}
+
+func (r *fileRecorder) Create(ctx context.Context, param *domain.Scale) error {
+	r.touch(param.Date)
+	return r.ScaleRepository.Create(ctx, param)
+}
+
+func (r *fileRecorder) Update(ctx context.Context, param *domain.Scale) error {
+	r.touch(param.Date)
+	return r.ScaleRepository.Update(ctx, param)
+}
+
+func (r *fileRecorder) Delete(ctx context.Context, date time.Time, version int) error {
+	r.touch(date)
+	return r.ScaleRepository.Delete(ctx, date, version)
+}
+
+func (r *fileRecorder) Restore(ctx context.Context, date time.Time) error {
+	r.touch(date)
+	return r.ScaleRepository.Restore(ctx, date)
+}
+
+// Purge only keeps the latest cut off, purging is the same before or after
+// the other writes since the days they touched are logged as they end up.
+func (r *fileRecorder) Purge(ctx context.Context, before time.Time) error {
+	if r.purge == nil || before.After(*r.purge) {
+		r.purge = &before
+	}
+	return r.ScaleRepository.Purge(ctx, before)
+}
+
+// Transaction keeps the purge and reset of fn only when it succeeds, the days
+// it touched are logged either way as they end up.
+func (r *fileRecorder) Transaction(ctx context.Context, fn func(tx domain.ScaleRepository) error) error {
+	return r.ScaleRepository.Transaction(ctx, func(tx domain.ScaleRepository) error {
+		inner := *r
+		inner.ScaleRepository = tx
+		err := fn(&inner)
+		if err != nil {
+			return err
+		}
+		r.purge, r.reset = inner.purge, inner.reset
+		return nil
+	})
+}
+
+func (r *fileRecorder) Load(ctx context.Context, snapshot *domain.ScaleSnapshot) error {
+	r.reset = true
+	return r.ScaleRepository.Load(ctx, snapshot)
+}
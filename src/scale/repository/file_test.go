@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileReopen(t *testing.T) {
+	now := time.Date(2022, 2, 3, 7, 0, 0, 0, helper.GetLocation())
+	original := helper.Now
+	helper.Now = func() time.Time { return now }
+	defer func() { helper.Now = original }()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	path := filepath.Join(t.TempDir(), "scales.log")
+	ctx := context.Background()
+	repo, err := NewFileScaleRepository(path, logrus.New())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, repo.Create(ctx, &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5}))
+	assert.NoError(t, repo.Create(ctx, &domain.Scale{Date: date.AddDate(0, 0, 1), Min: 46, Max: 50, Difference: 4}))
+	assert.NoError(t, repo.Update(ctx, &domain.Scale{Date: date, Min: 44, Max: 50}))
+	assert.NoError(t, repo.Delete(ctx, date.AddDate(0, 0, 1), 0))
+	assert.NoError(t, repo.Create(ctx, &domain.Scale{Date: date.AddDate(0, 0, 2), Min: 47, Max: 50, Difference: 3}))
+	assert.NoError(t, repo.Delete(ctx, date.AddDate(0, 0, 2), 0))
+	assert.NoError(t, repo.Restore(ctx, date.AddDate(0, 0, 2)))
+	assert.Equal(t, domain.ErrPreconditionFailed, repo.Delete(ctx, date, 1))
+	assert.NoError(t, repo.Transaction(ctx, func(tx domain.ScaleRepository) error {
+		err := tx.Create(ctx, &domain.Scale{Date: date.AddDate(0, 0, 3), Min: 48, Max: 50, Difference: 2})
+		if err != nil {
+			return err
+		}
+		return tx.Purge(ctx, now.Add(time.Hour))
+	}))
+	assert.Error(t, repo.Transaction(ctx, func(tx domain.ScaleRepository) error {
+		tx.Create(ctx, &domain.Scale{Date: date.AddDate(0, 0, 4), Min: 48, Max: 50, Difference: 2})
+		return errors.New("rolled back")
+	}))
+
+	want := dumpScales(t, repo)
+	assert.Len(t, want, 3)
+
+	assert.NoError(t, repo.(io.Closer).Close())
+	reopened, err := NewFileScaleRepository(path, logrus.New())
+	if assert.NoError(t, err) {
+		assertScales(t, want, dumpScales(t, reopened))
+	}
+}
+
+func TestFileCompact(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	path := filepath.Join(t.TempDir(), "scales.log")
+	ctx := context.Background()
+	repo, err := NewFileScaleRepository(path, logrus.New())
+	if !assert.NoError(t, err) {
+		return
+	}
+	repo.(*fileScaleRepository).compactAfter = 3
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, repo.Create(ctx, &domain.Scale{Date: date.AddDate(0, 0, i), Min: 45, Max: 50, Difference: 5}))
+	}
+	b, _ := ioutil.ReadFile(path)
+	assert.Equal(t, 2, strings.Count(string(b), "\n"), "compacted after the fourth write")
+
+	assert.NoError(t, repo.(io.Closer).Close())
+	reopened, err := NewFileScaleRepository(path, logrus.New())
+	if assert.NoError(t, err) {
+		assertScales(t, dumpScales(t, repo), dumpScales(t, reopened))
+	}
+}
+
+func TestFileLoad(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	path := filepath.Join(t.TempDir(), "scales.log")
+	ctx := context.Background()
+	repo, err := NewFileScaleRepository(path, logrus.New())
+	if !assert.NoError(t, err) {
+		return
+	}
+	repo.Create(ctx, &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5})
+	repo.Create(ctx, &domain.Scale{Date: date.AddDate(0, 0, 1), Min: 45, Max: 50, Difference: 5})
+
+	scales := []domain.Scale{{Date: date.AddDate(0, 0, 2), Min: 46, Max: 50, Difference: 4, Version: 3}}
+	assert.NoError(t, repo.Load(ctx, &domain.ScaleSnapshot{Scales: scales}))
+
+	b, _ := ioutil.ReadFile(path)
+	assert.Equal(t, 1, strings.Count(string(b), "\n"), "load rewrites the log")
+
+	assert.NoError(t, repo.(io.Closer).Close())
+	reopened, err := NewFileScaleRepository(path, logrus.New())
+	if assert.NoError(t, err) {
+		assertScales(t, scales, dumpScales(t, reopened))
+	}
+}
+
+func TestFileRecover(t *testing.T) {
+	good := `{"days":{"2022-02-01":[{"date":"2022-02-01T00:00:00+07:00","min":45,"max":50,"difference":5,"version":1}]}}` + "\n"
+
+	tests := []struct {
+		name       string
+		args       string
+		wantScales int
+		wantSize   int
+		wantErr    bool
+	}{
+		{
+			name:       "empty",
+			args:       "",
+			wantScales: 0,
+		},
+		{
+			name:       "whole",
+			args:       good,
+			wantScales: 1,
+			wantSize:   len(good),
+		},
+		{
+			name:       "torn last record",
+			args:       good + `{"days":{"2022-02-02":[{"date":"2022-02-02T00:0`,
+			wantScales: 1,
+			wantSize:   len(good),
+		},
+		{
+			name:       "last record without newline",
+			args:       good + strings.TrimSuffix(good, "\n"),
+			wantScales: 1,
+			wantSize:   len(good),
+		},
+		{
+			name:    "broken record",
+			args:    `{"days":` + "\n" + good,
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "scales.log")
+			ioutil.WriteFile(path, []byte(test.args), 0600)
+			// a compaction cut short
+			ioutil.WriteFile(path+".tmp", []byte(`{"reset":`), 0600)
+
+			repo, err := NewFileScaleRepository(path, logrus.New())
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Len(t, dumpScales(t, repo), test.wantScales)
+			info, _ := os.Stat(path)
+			assert.Equal(t, int64(test.wantSize), info.Size())
+			_, err = os.Stat(path + ".tmp")
+			assert.True(t, os.IsNotExist(err))
+
+			// writes land after the last whole record
+			err = repo.Create(context.Background(), &domain.Scale{Date: time.Date(2022, 2, 3, 0, 0, 0, 0, helper.GetLocation())})
+			assert.NoError(t, err)
+			assert.NoError(t, repo.(io.Closer).Close())
+			reopened, err := NewFileScaleRepository(path, logrus.New())
+			if assert.NoError(t, err) {
+				assert.Len(t, dumpScales(t, reopened), test.wantScales+1)
+			}
+		})
+	}
+}
+
+func TestFileLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scales.log")
+	repo, err := NewFileScaleRepository(path, logrus.New())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = NewFileScaleRepository(path, logrus.New())
+	assert.EqualError(t, err, path+" is in use by another process")
+
+	assert.NoError(t, repo.(io.Closer).Close())
+	reopened, err := NewFileScaleRepository(path, logrus.New())
+	if assert.NoError(t, err) {
+		reopened.(io.Closer).Close()
+	}
+}
+
+// failingFile fails every write after writing half of it, and truncates only
+// when truncateErr is nil.
+type failingFile struct {
+	*os.File
+	truncateErr error
+}
+
+func (f *failingFile) Write(b []byte) (int, error) {
+	n, _ := f.File.Write(b[:len(b)/2])
+	return n, errors.New("disk full")
+}
+
+func (f *failingFile) Truncate(size int64) error {
+	if f.truncateErr != nil {
+		return f.truncateErr
+	}
+	return f.File.Truncate(size)
+}
+
+func TestFileWriteError(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		args        error
+		wantRefused bool
+	}{
+		{
+			name: "taken back",
+		},
+		{
+			name:        "truncate error",
+			args:        errors.New("io error"),
+			wantRefused: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "scales.log")
+			repo, err := NewFileScaleRepository(path, logrus.New())
+			if !assert.NoError(t, err) {
+				return
+			}
+			f := repo.(*fileScaleRepository)
+			assert.NoError(t, repo.Create(ctx, &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5}))
+			before, _ := ioutil.ReadFile(path)
+
+			working := f.file
+			f.file = &failingFile{File: working.(*os.File), truncateErr: test.args}
+			assert.EqualError(t, repo.Create(ctx, &domain.Scale{Date: date.AddDate(0, 0, 1), Min: 45, Max: 50, Difference: 5}), "disk full")
+			assert.Len(t, dumpScales(t, repo), 1)
+
+			f.file = working
+			err = repo.Create(ctx, &domain.Scale{Date: date.AddDate(0, 0, 2), Min: 45, Max: 50, Difference: 5})
+			if test.wantRefused {
+				assert.EqualError(t, err, path+": log unusable after a failed write: io error")
+				assert.EqualError(t, repo.Health(ctx), path+": log unusable after a failed write: io error")
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, repo.Health(ctx))
+			after, _ := ioutil.ReadFile(path)
+			assert.True(t, strings.HasPrefix(string(after), string(before)))
+
+			assert.NoError(t, repo.(io.Closer).Close())
+			reopened, err := NewFileScaleRepository(path, logrus.New())
+			if assert.NoError(t, err) {
+				assertScales(t, dumpScales(t, repo), dumpScales(t, reopened))
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+)
+
+type scaleHistoryRepository struct {
+	mu      sync.RWMutex
+	history []domain.ScaleHistory // asume this is db
+}
+
+func NewScaleHistoryRepository() domain.ScaleHistoryRepository {
+	return &scaleHistoryRepository{}
+}
+
+func (s *scaleHistoryRepository) Create(param *domain.ScaleHistory) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history = append(s.history, *param)
+	return nil
+}
+
+func (s *scaleHistoryRepository) GetHistory(date time.Time) ([]domain.ScaleHistory, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history := []domain.ScaleHistory{}
+	for _, entry := range s.history {
+		if entry.Date.Format(common.TimeLayout) == date.Format(common.TimeLayout) {
+			history = append(history, entry)
+		}
+	}
+	return history, nil
+}
+
+func (s *scaleHistoryRepository) Dump() ([]domain.ScaleHistory, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]domain.ScaleHistory{}, s.history...), nil
+}
+
+func (s *scaleHistoryRepository) Load(history []domain.ScaleHistory) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history = append([]domain.ScaleHistory{}, history...)
+	return nil
+}
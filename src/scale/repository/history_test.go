@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHistory(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	repo := &scaleHistoryRepository{}
+	repo.Create(&domain.ScaleHistory{Date: date, Action: domain.ScaleCreated})
+	repo.Create(&domain.ScaleHistory{Date: date.AddDate(0, 0, 1), Action: domain.ScaleCreated})
+	repo.Create(&domain.ScaleHistory{Date: date, Action: domain.ScaleUpdated})
+
+	tests := []struct {
+		name       string
+		args       time.Time
+		wantResult []domain.ScaleHistory
+	}{
+		{
+			name: "success",
+			args: date,
+			wantResult: []domain.ScaleHistory{
+				{Date: date, Action: domain.ScaleCreated},
+				{Date: date, Action: domain.ScaleUpdated},
+			},
+		},
+		{
+			name:       "empty",
+			args:       date.AddDate(0, 0, 2),
+			wantResult: []domain.ScaleHistory{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := repo.GetHistory(test.args)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantResult, got)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+)
+
+var (
+	operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "scale_repository_operation_duration_seconds",
+		Help:    "Latency of scale repository operations.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation"})
+	operationErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "scale_repository_operation_errors_total",
+		Help: "Scale repository operations that returned an error.",
+	}, []string{"operation"})
+
+	readingsDesc = prometheus.NewDesc(
+		"scale_readings",
+		"Readings currently stored, deleted ones excluded.",
+		nil, nil,
+	)
+	latestWeightDesc = prometheus.NewDesc(
+		"scale_latest_weight",
+		"Min and max weight of the newest reading.",
+		[]string{"bound"}, nil,
+	)
+)
+
+type metricsScaleRepository struct {
+	domain.ScaleRepository
+}
+
+// NewMetricsScaleRepository wraps any ScaleRepository, recording the latency
+// and errors of every operation. Health is left out so readiness probes do not
+// skew the latencies.
+func NewMetricsScaleRepository(scaleRepository domain.ScaleRepository) domain.ScaleRepository {
+	return &metricsScaleRepository{
+		ScaleRepository: scaleRepository,
+	}
+}
+
+func observe(operation string, start time.Time, err error) {
+	operationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		operationErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+func (m *metricsScaleRepository) Create(ctx context.Context, param *domain.Scale) error {
+	start := time.Now()
+	err := m.ScaleRepository.Create(ctx, param)
+	observe("create", start, err)
+	return err
+}
+
+func (m *metricsScaleRepository) GetScales(ctx context.Context) ([]domain.Scale, error) {
+	start := time.Now()
+	scales, err := m.ScaleRepository.GetScales(ctx)
+	observe("get_scales", start, err)
+	return scales, err
+}
+
+func (m *metricsScaleRepository) GetScale(ctx context.Context, date time.Time) ([]domain.Scale, error) {
+	start := time.Now()
+	scales, err := m.ScaleRepository.GetScale(ctx, date)
+	observe("get_scale", start, err)
+	return scales, err
+}
+
+func (m *metricsScaleRepository) Update(ctx context.Context, param *domain.Scale) error {
+	start := time.Now()
+	err := m.ScaleRepository.Update(ctx, param)
+	observe("update", start, err)
+	return err
+}
+
+func (m *metricsScaleRepository) Delete(ctx context.Context, date time.Time, version int) error {
+	start := time.Now()
+	err := m.ScaleRepository.Delete(ctx, date, version)
+	observe("delete", start, err)
+	return err
+}
+
+func (m *metricsScaleRepository) GetTrash(ctx context.Context) ([]domain.Scale, error) {
+	start := time.Now()
+	scales, err := m.ScaleRepository.GetTrash(ctx)
+	observe("get_trash", start, err)
+	return scales, err
+}
+
+func (m *metricsScaleRepository) Restore(ctx context.Context, date time.Time) error {
+	start := time.Now()
+	err := m.ScaleRepository.Restore(ctx, date)
+	observe("restore", start, err)
+	return err
+}
+
+func (m *metricsScaleRepository) Purge(ctx context.Context, before time.Time) error {
+	start := time.Now()
+	err := m.ScaleRepository.Purge(ctx, before)
+	observe("purge", start, err)
+	return err
+}
+
+func (m *metricsScaleRepository) Dump(ctx context.Context) (*domain.ScaleSnapshot, error) {
+	start := time.Now()
+	snapshot, err := m.ScaleRepository.Dump(ctx)
+	observe("dump", start, err)
+	return snapshot, err
+}
+
+func (m *metricsScaleRepository) Load(ctx context.Context, snapshot *domain.ScaleSnapshot) error {
+	start := time.Now()
+	err := m.ScaleRepository.Load(ctx, snapshot)
+	observe("load", start, err)
+	return err
+}
+
+// Transaction also records the operations of fn on their own.
+func (m *metricsScaleRepository) Transaction(ctx context.Context, fn func(tx domain.ScaleRepository) error) error {
+	start := time.Now()
+	err := m.ScaleRepository.Transaction(ctx, func(tx domain.ScaleRepository) error {
+		return fn(NewMetricsScaleRepository(tx))
+	})
+	observe("transaction", start, err)
+	return err
+}
+
+// scaleCollectInterval is how long a scrape reuses the readings of the one
+// before, so frequent or parallel scrapers do not each read the whole backend.
+const scaleCollectInterval = 30 * time.Second
+
+type scaleCollector struct {
+	scaleRepository domain.ScaleRepository
+
+	mu        sync.Mutex
+	collected time.Time
+	readings  int
+	latest    *domain.Scale
+}
+
+// NewScaleCollector reports the number of readings and the latest weight of
+// scaleRepository, reading it again at most every scaleCollectInterval.
+func NewScaleCollector(scaleRepository domain.ScaleRepository) prometheus.Collector {
+	return &scaleCollector{
+		scaleRepository: scaleRepository,
+	}
+}
+
+func (c *scaleCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- readingsDesc
+	ch <- latestWeightDesc
+}
+
+func (c *scaleCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now := helper.Now(); c.collected.IsZero() || now.Sub(c.collected) >= scaleCollectInterval {
+		scales, err := c.scaleRepository.GetScales(context.Background())
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(readingsDesc, err)
+			return
+		}
+		c.collected = now
+		c.readings = len(scales)
+		c.latest = nil
+		if len(scales) > 0 {
+			// newest first
+			c.latest = &scales[0]
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(readingsDesc, prometheus.GaugeValue, float64(c.readings))
+	if c.latest == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(latestWeightDesc, prometheus.GaugeValue, float64(c.latest.Min), "min")
+	ch <- prometheus.MustNewConstMetric(latestWeightDesc, prometheus.GaugeValue, float64(c.latest.Max), "max")
+}
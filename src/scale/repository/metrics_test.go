@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	scaleMock := mock_domain.NewMockScaleRepository(ctrl)
+	repo := NewMetricsScaleRepository(scaleMock)
+	counter := operationErrors.WithLabelValues("delete")
+
+	tests := []struct {
+		name       string
+		wantErrors float64
+		mock       func()
+	}{
+		{
+			name:       "success",
+			wantErrors: 0,
+			mock: func() {
+				scaleMock.EXPECT().Delete(gomock.Any(), date, 1).Return(nil)
+			},
+		},
+		{
+			name:       "error",
+			wantErrors: 1,
+			mock: func() {
+				scaleMock.EXPECT().Delete(gomock.Any(), date, 1).Return(errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+			before := testutil.ToFloat64(counter)
+
+			repo.Delete(context.Background(), date, 1)
+			assert.Equal(t, before+test.wantErrors, testutil.ToFloat64(counter))
+		})
+	}
+}
+
+func TestScaleCollector(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+
+	tests := []struct {
+		name       string
+		args       []domain.Scale
+		wantResult string
+	}{
+		{
+			name: "readings",
+			args: []domain.Scale{
+				{Date: date, Min: 45, Max: 50, Difference: 5},
+				{Date: date.AddDate(0, 0, 1), Min: 47, Max: 49, Difference: 2},
+			},
+			wantResult: `
+# HELP scale_latest_weight Min and max weight of the newest reading.
+# TYPE scale_latest_weight gauge
+scale_latest_weight{bound="max"} 49
+scale_latest_weight{bound="min"} 47
+# HELP scale_readings Readings currently stored, deleted ones excluded.
+# TYPE scale_readings gauge
+scale_readings 2
+`,
+		},
+		{
+			name: "empty",
+			wantResult: `
+# HELP scale_readings Readings currently stored, deleted ones excluded.
+# TYPE scale_readings gauge
+scale_readings 0
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &scaleRepository{scales: test.args}
+
+			err := testutil.CollectAndCompare(NewScaleCollector(repo), strings.NewReader(test.wantResult))
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestScaleCollectorInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2022, 2, 1, 12, 0, 0, 0, helper.GetLocation())
+	original := helper.Now
+	helper.Now = func() time.Time { return now }
+	defer func() { helper.Now = original }()
+
+	repo := mock_domain.NewMockScaleRepository(ctrl)
+	repo.EXPECT().GetScales(gomock.Any()).Return([]domain.Scale{{Date: now, Min: 45, Max: 50, Difference: 5}}, nil).Times(2)
+	collector := NewScaleCollector(repo)
+
+	assert.Equal(t, 3, testutil.CollectAndCount(collector))
+	now = now.Add(scaleCollectInterval - time.Second)
+	assert.Equal(t, 3, testutil.CollectAndCount(collector))
+	now = now.Add(time.Second)
+	assert.Equal(t, 3, testutil.CollectAndCount(collector))
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scale/src/domain"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	StorageMemory = "memory"
+	// StorageFile is followed by the path of the log, as in file:/var/lib/scale/scales.log
+	StorageFile = "file"
+	// StorageBolt is followed by the path of the database, as in bolt:/var/lib/scale/scales.db
+	StorageBolt = "bolt"
+)
+
+// storages open the persistent storages from their path
+var storages = map[string]func(path string, logger logrus.FieldLogger) (domain.ScaleRepository, error){
+	StorageFile: NewFileScaleRepository,
+	StorageBolt: NewBoltScaleRepository,
+}
+
+// Open returns the scale repository kept in storage, the SCALE_STORAGE
+// setting, memory when empty. Only one process may open a file or bolt storage
+// at a time, both enforce it with a lock. logger reports what the storage does
+// outside of requests.
+func Open(storage string, logger logrus.FieldLogger) (domain.ScaleRepository, error) {
+	switch storage {
+	case "", StorageMemory:
+		return NewScaleRepository(), nil
+	}
+	name := strings.SplitN(storage, ":", 2)
+	open, ok := storages[name[0]]
+	if !ok || len(name) == 1 {
+		return nil, fmt.Errorf("unknown storage %q", storage)
+	}
+	if name[1] == "" {
+		return nil, fmt.Errorf("storage %q has no path", storage)
+	}
+	return open(name[1], logger)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scales.log")
+
+	tests := []struct {
+		name    string
+		args    string
+		wantErr bool
+	}{
+		{name: "default", args: ""},
+		{name: "memory", args: StorageMemory},
+		{name: "file", args: StorageFile + ":" + path},
+		{name: "file without path", args: StorageFile + ":", wantErr: true},
+		{name: "unknown", args: "postgres://localhost", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo, err := Open(test.args, logrus.New())
+			assert.Equal(t, test.wantErr, err != nil)
+			assert.Equal(t, test.wantErr, repo == nil)
+		})
+	}
+}
@@ -1,14 +1,21 @@
 package repository
 
 import (
+	"context"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/scale/src/common"
 	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
 )
 
+// scaleRepository gives up with ctx.Err() once the lock is taken if ctx is done
+// by then, so requests queued behind a long transaction do not outlive their
+// deadline.
 type scaleRepository struct {
+	mu     sync.RWMutex
 	scales []domain.Scale // asume this is db
 }
 
@@ -16,23 +23,73 @@ func NewScaleRepository() domain.ScaleRepository {
 	return &scaleRepository{}
 }
 
-func (s *scaleRepository) Create(param *domain.Scale) error {
+// versions returns the current version of the readings of date and the last
+// version given to any of them, deleted ones included, so versions never repeat.
+func (s *scaleRepository) versions(date time.Time) (current, last int) {
+	for _, scale := range s.scales {
+		if scale.Date.Format(common.TimeLayout) != date.Format(common.TimeLayout) {
+			continue
+		}
+		if scale.Version > last {
+			last = scale.Version
+		}
+		if scale.DeletedAt == nil && scale.Version > current {
+			current = scale.Version
+		}
+	}
+	return current, last
+}
+
+func (s *scaleRepository) Create(ctx context.Context, param *domain.Scale) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	_, last := s.versions(param.Date)
+	param.Version = last + 1
+
 	s.scales = append(s.scales, *param)
 	return nil
 }
 
-func (s *scaleRepository) GetScales() ([]domain.Scale, error) {
-	sort.Slice(s.scales, func(i, j int) bool {
-		return s.scales[i].Date.After(s.scales[j].Date)
-	})
+func (s *scaleRepository) GetScales(ctx context.Context) ([]domain.Scale, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	return s.scales, nil
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	scaleResponse := []domain.Scale{}
+	for _, scale := range s.scales {
+		if scale.DeletedAt == nil {
+			scaleResponse = append(scaleResponse, scale)
+		}
+	}
+
+	sort.Slice(scaleResponse, func(i, j int) bool {
+		return scaleResponse[i].Date.After(scaleResponse[j].Date)
+	})
+	return scaleResponse, nil
 }
 
-func (s *scaleRepository) GetScale(date time.Time) ([]domain.Scale, error) {
+func (s *scaleRepository) GetScale(ctx context.Context, date time.Time) ([]domain.Scale, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
 	scaleResponse := []domain.Scale{}
 	for _, scale := range s.scales {
-		if scale.Date.Format(common.TimeLayout) == date.Format(common.TimeLayout) {
+		if scale.DeletedAt == nil && scale.Date.Format(common.TimeLayout) == date.Format(common.TimeLayout) {
 			scaleResponse = append(scaleResponse, scale)
 		}
 	}
@@ -43,24 +100,193 @@ func (s *scaleRepository) GetScale(date time.Time) ([]domain.Scale, error) {
 	return scaleResponse, nil
 }
 
-func (s *scaleRepository) Update(param *domain.Scale) error {
+func (s *scaleRepository) Update(ctx context.Context, param *domain.Scale) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	current, last := s.versions(param.Date)
+	if param.Version != 0 && param.Version != current {
+		return domain.ErrPreconditionFailed
+	}
+
 	for i, scale := range s.scales {
-		if scale.Date.Format(common.TimeLayout) == param.Date.Format(common.TimeLayout) {
+		if scale.DeletedAt == nil && scale.Date.Format(common.TimeLayout) == param.Date.Format(common.TimeLayout) {
 			s.scales[i].Min = param.Min
 			s.scales[i].Max = param.Max
 			s.scales[i].Difference = param.Max - param.Min
+			s.scales[i].Version = last + 1
+			param.Version = last + 1
+		}
+	}
+
+	return nil
+}
+
+func (s *scaleRepository) Delete(ctx context.Context, date time.Time, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	current, last := s.versions(date)
+	if version != 0 && version != current {
+		return domain.ErrPreconditionFailed
+	}
+
+	deletedAt := helper.Now()
+	for i, scale := range s.scales {
+		if scale.DeletedAt == nil && scale.Date.Format(common.TimeLayout) == date.Format(common.TimeLayout) {
+			s.scales[i].DeletedAt = &deletedAt
+			s.scales[i].Version = last + 1
 		}
 	}
 
 	return nil
 }
 
-func (s *scaleRepository) Delete(date time.Time) error {
+func (s *scaleRepository) GetTrash(ctx context.Context) ([]domain.Scale, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	scaleResponse := []domain.Scale{}
+	for _, scale := range s.scales {
+		if scale.DeletedAt != nil {
+			scaleResponse = append(scaleResponse, scale)
+		}
+	}
+
+	sort.Slice(scaleResponse, func(i, j int) bool {
+		return scaleResponse[i].DeletedAt.After(*scaleResponse[j].DeletedAt)
+	})
+	return scaleResponse, nil
+}
+
+func (s *scaleRepository) Restore(ctx context.Context, date time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	_, last := s.versions(date)
+	deleted, live := []int{}, false
 	for i, scale := range s.scales {
-		if scale.Date.Format(common.TimeLayout) == date.Format(common.TimeLayout) {
-			s.scales = remove(s.scales, i)
+		if scale.Date.Format(common.TimeLayout) != date.Format(common.TimeLayout) {
+			continue
+		}
+		if scale.DeletedAt != nil {
+			deleted = append(deleted, i)
+		} else {
+			live = true
 		}
 	}
+	if len(deleted) == 0 {
+		return domain.ErrNotFound
+	}
+	// the deleted readings would end up next to the live ones
+	if live {
+		return domain.ErrConflict
+	}
+
+	for _, i := range deleted {
+		s.scales[i].DeletedAt = nil
+		s.scales[i].Version = last + 1
+	}
+	return nil
+}
+
+func (s *scaleRepository) Purge(ctx context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	scales := s.scales[:0]
+	for _, scale := range s.scales {
+		if scale.DeletedAt == nil || !scale.DeletedAt.Before(before) {
+			scales = append(scales, scale)
+		}
+	}
+	s.scales = scales
+
+	return nil
+}
+
+// Transaction runs fn against a copy of the readings, keeping its writes only
+// when fn succeeds. Other writers wait until it is done.
+func (s *scaleRepository) Transaction(ctx context.Context, fn func(tx domain.ScaleRepository) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	tx := &scaleRepository{
+		scales: append([]domain.Scale{}, s.scales...),
+	}
+	err = fn(tx)
+	if err != nil {
+		helper.GetLogger(ctx).WithError(err).Debug("transaction rolled back")
+		return err
+	}
+
+	s.scales = tx.scales
+	return nil
+}
+
+// Health reports whether the repository can serve reads, which for memory only
+// fails when ctx is done before a write in progress lets go of the lock.
+func (s *scaleRepository) Health(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return ctx.Err()
+}
+
+// Dump leaves History to the audit decorator.
+func (s *scaleRepository) Dump(ctx context.Context) (*domain.ScaleSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ScaleSnapshot{
+		Scales: append([]domain.Scale{}, s.scales...),
+	}, nil
+}
+
+func (s *scaleRepository) Load(ctx context.Context, snapshot *domain.ScaleSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
 
+	s.scales = append([]domain.Scale{}, snapshot.Scales...)
 	return nil
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"reflect"
 	"testing"
 	"time"
@@ -45,7 +46,7 @@ func TestCreate(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			test.mock()
-			err := repo.Create(test.args.param)
+			err := repo.Create(context.Background(), test.args.param)
 			assert.Equal(t, test.wantErr, err != nil)
 		})
 	}
@@ -119,7 +120,7 @@ func TestGetScales(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mock()
 
-			got, err := repo.GetScales()
+			got, err := repo.GetScales(context.Background())
 			assert.Equal(t, test.wantErr, err != nil)
 			assert.Equal(t, test.wantResult, got)
 		})
@@ -172,7 +173,7 @@ func TestGetScale(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			test.mock()
-			got, err := repo.GetScale(test.args.date)
+			got, err := repo.GetScale(context.Background(), test.args.date)
 			assert.Equal(t, test.wantErr, err != nil)
 			assert.Equal(t, test.wantResult, got)
 		})
@@ -221,55 +222,282 @@ func TestUpdate(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			test.mock()
-			err := repo.Update(test.args.param)
+			err := repo.Update(context.Background(), test.args.param)
 			assert.Equal(t, test.wantErr, err != nil)
 		})
 	}
 }
 
 func TestDelete(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	now := time.Date(2022, 2, 2, 7, 0, 0, 0, helper.GetLocation())
+	original := helper.Now
+	helper.Now = func() time.Time { return now }
+	defer func() { helper.Now = original }()
+
 	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	deletedAt := now.AddDate(0, 0, -1)
+
+	repo := &scaleRepository{
+		scales: []domain.Scale{
+			{Date: date, Min: 45, Max: 50, Difference: 5, Version: 2},
+			{Date: date, Min: 46, Max: 50, Difference: 4, Version: 1, DeletedAt: &deletedAt},
+			{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3, Version: 1},
+		},
+	}
+
+	err := repo.Delete(context.Background(), date, 1)
+	assert.Equal(t, domain.ErrPreconditionFailed, err)
 
+	err = repo.Delete(context.Background(), date, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Scale{
+		{Date: date, Min: 45, Max: 50, Difference: 5, Version: 3, DeletedAt: &now},
+		{Date: date, Min: 46, Max: 50, Difference: 4, Version: 1, DeletedAt: &deletedAt},
+		{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3, Version: 1},
+	}, repo.scales)
+
+	scales, _ := repo.GetScales(context.Background())
+	assert.Equal(t, []domain.Scale{{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3, Version: 1}}, scales)
+	scales, _ = repo.GetScale(context.Background(), date)
+	assert.Equal(t, []domain.Scale{}, scales)
+
+	// deleted readings are left untouched by updates
+	repo.Update(context.Background(), &domain.Scale{Date: date, Min: 40, Max: 50})
+	trash, _ := repo.GetTrash(context.Background())
+	assert.Equal(t, []domain.Scale{
+		{Date: date, Min: 45, Max: 50, Difference: 5, Version: 3, DeletedAt: &now},
+		{Date: date, Min: 46, Max: 50, Difference: 4, Version: 1, DeletedAt: &deletedAt},
+	}, trash)
+}
+
+func TestVersion(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
 	repo := &scaleRepository{}
 
-	type args struct {
-		date time.Time
+	first := &domain.Scale{Date: date, Min: 45, Max: 50}
+	assert.NoError(t, repo.Create(context.Background(), first))
+	assert.Equal(t, 1, first.Version)
+
+	second := &domain.Scale{Date: date, Min: 46, Max: 50}
+	assert.NoError(t, repo.Create(context.Background(), second))
+	assert.Equal(t, 2, second.Version)
+
+	other := &domain.Scale{Date: date.AddDate(0, 0, 1), Min: 46, Max: 50}
+	assert.NoError(t, repo.Create(context.Background(), other))
+	assert.Equal(t, 1, other.Version)
+
+	tests := []struct {
+		name        string
+		args        *domain.Scale
+		wantVersion int
+		wantErr     error
+	}{
+		{
+			name:    "stale",
+			args:    &domain.Scale{Date: date, Min: 44, Max: 50, Version: 1},
+			wantErr: domain.ErrPreconditionFailed,
+		},
+		{
+			name:        "current",
+			args:        &domain.Scale{Date: date, Min: 44, Max: 50, Version: 2},
+			wantVersion: 3,
+		},
+		{
+			name:        "unconditional",
+			args:        &domain.Scale{Date: date, Min: 43, Max: 50},
+			wantVersion: 4,
+		},
+		{
+			name:    "no reading",
+			args:    &domain.Scale{Date: date.AddDate(0, 0, 2), Min: 43, Max: 50, Version: 1},
+			wantErr: domain.ErrPreconditionFailed,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := repo.Update(context.Background(), test.args)
+			assert.Equal(t, test.wantErr, err)
+			if err == nil {
+				assert.Equal(t, test.wantVersion, test.args.Version)
+			}
+		})
+	}
+
+	scales, _ := repo.GetScale(context.Background(), date)
+	for _, scale := range scales {
+		assert.Equal(t, 4, scale.Version)
+		assert.Equal(t, 43, scale.Min)
+	}
+
+	// versions keep increasing across deletes so a stale tag never matches again
+	assert.NoError(t, repo.Delete(context.Background(), date, 4))
+	assert.NoError(t, repo.Create(context.Background(), first))
+	assert.Equal(t, 6, first.Version)
+}
+
+func TestRestore(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	deletedAt := date.AddDate(0, 0, 1)
+
+	repo := &scaleRepository{
+		scales: []domain.Scale{
+			{Date: date, Min: 45, Max: 50, Difference: 5, Version: 2, DeletedAt: &deletedAt},
+			{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3, Version: 1},
+		},
 	}
+
 	tests := []struct {
 		name    string
-		args    args
-		wantErr bool
-		mock    func()
+		args    time.Time
+		wantErr error
 	}{
 		{
 			name: "success",
-			args: args{
-				date: date,
+			args: date,
+		},
+		{
+			name:    "not in trash",
+			args:    date.AddDate(0, 0, 1),
+			wantErr: domain.ErrNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := repo.Restore(context.Background(), test.args)
+			assert.Equal(t, test.wantErr, err)
+		})
+	}
+
+	scales, _ := repo.GetScale(context.Background(), date)
+	assert.Equal(t, []domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5, Version: 3}}, scales)
+}
+
+func TestPurge(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	old := date.AddDate(0, 0, -31)
+	recent := date.AddDate(0, 0, -1)
+
+	repo := &scaleRepository{
+		scales: []domain.Scale{
+			{Date: date, Min: 45, Max: 50, Difference: 5, DeletedAt: &old},
+			{Date: date, Min: 46, Max: 50, Difference: 4, DeletedAt: &recent},
+			{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3},
+		},
+	}
+
+	err := repo.Purge(context.Background(), date.AddDate(0, 0, -30))
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Scale{
+		{Date: date, Min: 46, Max: 50, Difference: 4, DeletedAt: &recent},
+		{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3},
+	}, repo.scales)
+}
+
+func TestTransaction(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+
+	tests := []struct {
+		name       string
+		args       func(tx domain.ScaleRepository) error
+		wantResult []domain.Scale
+		wantErr    error
+	}{
+		{
+			name: "commit",
+			args: func(tx domain.ScaleRepository) error {
+				err := tx.Create(context.Background(), &domain.Scale{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3})
+				if err != nil {
+					return err
+				}
+				return tx.Delete(context.Background(), date, 0)
 			},
-			wantErr: false,
-			mock: func() {
-				repo.scales = []domain.Scale{
-					{
-						Date:       date,
-						Min:        45,
-						Max:        50,
-						Difference: 5,
-					},
+			wantResult: []domain.Scale{
+				{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3, Version: 1},
+			},
+		},
+		{
+			name: "rollback",
+			args: func(tx domain.ScaleRepository) error {
+				err := tx.Create(context.Background(), &domain.Scale{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3})
+				if err != nil {
+					return err
 				}
+				return tx.Delete(context.Background(), date, 2)
+			},
+			wantResult: []domain.Scale{
+				{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1},
 			},
+			wantErr: domain.ErrPreconditionFailed,
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			test.mock()
-			err := repo.Delete(test.args.date)
-			assert.Equal(t, test.wantErr, err != nil)
+			repo := &scaleRepository{
+				scales: []domain.Scale{
+					{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1},
+				},
+			}
+
+			err := repo.Transaction(context.Background(), test.args)
+			assert.Equal(t, test.wantErr, err)
+
+			got, _ := repo.GetScales(context.Background())
+			assert.Equal(t, test.wantResult, got)
 		})
 	}
 }
 
+func TestContextDone(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	repo := &scaleRepository{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := repo.Create(ctx, &domain.Scale{Date: date, Min: 45, Max: 50})
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = repo.GetScales(ctx)
+	assert.Equal(t, context.Canceled, err)
+
+	err = repo.Transaction(ctx, func(tx domain.ScaleRepository) error {
+		t.Error("transaction ran after its context was done")
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, repo.scales)
+
+	assert.Equal(t, context.Canceled, repo.Health(ctx))
+	assert.NoError(t, repo.Health(context.Background()))
+}
+
+func TestDumpLoad(t *testing.T) {
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	deletedAt := date.Add(time.Hour)
+	scales := []domain.Scale{
+		{Date: date, Min: 45, Max: 50, Difference: 5, Version: 2, DeletedAt: &deletedAt},
+		{Date: date.AddDate(0, 0, 1), Min: 46, Max: 49, Difference: 3, Version: 1},
+	}
+	repo := &scaleRepository{}
+	ctx := context.Background()
+
+	err := repo.Load(ctx, &domain.ScaleSnapshot{Scales: scales})
+	assert.NoError(t, err)
+	scales[1].Min = 0
+
+	got, err := repo.Dump(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 46, got.Scales[1].Min, "load keeps its own copy")
+	assert.Equal(t, scales[0], got.Scales[0])
+	assert.Nil(t, got.History)
+
+	got.Scales[0].Min = 0
+	assert.Equal(t, 45, repo.scales[0].Min, "dump hands out a copy")
+
+	live, err := repo.GetScales(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, live, 1)
+}
+
 func Test_scaleRepository_GetScales(t *testing.T) {
 	type fields struct {
 		scales []domain.Scale
@@ -287,7 +515,7 @@ func Test_scaleRepository_GetScales(t *testing.T) {
 			s := &scaleRepository{
 				scales: tt.fields.scales,
 			}
-			got, err := s.GetScales()
+			got, err := s.GetScales(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("scaleRepository.GetScales() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,342 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// dumpScales returns everything stored by date then version, Dump itself
+// promises no order.
+func dumpScales(t *testing.T, repo domain.ScaleRepository) []domain.Scale {
+	snapshot, err := repo.Dump(context.Background())
+	assert.NoError(t, err)
+	scales := snapshot.Scales
+	sort.SliceStable(scales, func(i, j int) bool {
+		if !scales[i].Date.Equal(scales[j].Date) {
+			return scales[i].Date.Before(scales[j].Date)
+		}
+		return scales[i].Version < scales[j].Version
+	})
+	return scales
+}
+
+// assertScales compares readings by value, times read back from storage are
+// equal but not ==.
+func assertScales(t *testing.T, want, got []domain.Scale) {
+	if !assert.Len(t, got, len(want)) {
+		return
+	}
+	for i := range want {
+		assert.True(t, want[i].Date.Equal(got[i].Date), "date of %d", i)
+		assert.Equal(t, want[i].Min, got[i].Min, "min of %d", i)
+		assert.Equal(t, want[i].Max, got[i].Max, "max of %d", i)
+		assert.Equal(t, want[i].Difference, got[i].Difference, "difference of %d", i)
+		assert.Equal(t, want[i].Version, got[i].Version, "version of %d", i)
+		if want[i].DeletedAt == nil {
+			assert.Nil(t, got[i].DeletedAt, "deleted at of %d", i)
+		} else if assert.NotNil(t, got[i].DeletedAt, "deleted at of %d", i) {
+			assert.True(t, want[i].DeletedAt.Equal(*got[i].DeletedAt), "deleted at of %d", i)
+		}
+	}
+}
+
+// testScaleRepository is the behavior every ScaleRepository shares, newRepo
+// returns an empty one.
+func testScaleRepository(t *testing.T, newRepo func(t *testing.T) domain.ScaleRepository) {
+	now := time.Date(2022, 2, 2, 7, 0, 0, 0, helper.GetLocation())
+	original := helper.Now
+	helper.Now = func() time.Time { return now }
+	defer func() { helper.Now = original }()
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	old := now.AddDate(0, 0, -31)
+	ctx := context.Background()
+
+	// seed loads scales into a new repository
+	seed := func(t *testing.T, scales ...domain.Scale) domain.ScaleRepository {
+		repo := newRepo(t)
+		err := repo.Load(ctx, &domain.ScaleSnapshot{Scales: scales})
+		assert.NoError(t, err)
+		return repo
+	}
+
+	t.Run("get scales newest first", func(t *testing.T) {
+		repo := seed(t,
+			domain.Scale{Date: date.AddDate(0, 0, -3), Min: 45, Max: 50, Difference: 5, Version: 1},
+			domain.Scale{Date: date, Min: 47, Max: 50, Difference: 3, Version: 1},
+			domain.Scale{Date: date.AddDate(0, 0, -1), Min: 46, Max: 50, Difference: 4, Version: 2, DeletedAt: &old},
+			domain.Scale{Date: date.AddDate(0, 0, 10), Min: 44, Max: 50, Difference: 6, Version: 1},
+		)
+
+		got, err := repo.GetScales(ctx)
+		assert.NoError(t, err)
+		assertScales(t, []domain.Scale{
+			{Date: date.AddDate(0, 0, 10), Min: 44, Max: 50, Difference: 6, Version: 1},
+			{Date: date, Min: 47, Max: 50, Difference: 3, Version: 1},
+			{Date: date.AddDate(0, 0, -3), Min: 45, Max: 50, Difference: 5, Version: 1},
+		}, got)
+
+		got, err = repo.GetScale(ctx, date.AddDate(0, 0, -3))
+		assert.NoError(t, err)
+		assertScales(t, []domain.Scale{{Date: date.AddDate(0, 0, -3), Min: 45, Max: 50, Difference: 5, Version: 1}}, got)
+
+		got, err = repo.GetScale(ctx, date.AddDate(0, 0, -1))
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Scale{}, got)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		repo := newRepo(t)
+
+		got, err := repo.GetScales(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Scale{}, got)
+		got, err = repo.GetTrash(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Scale{}, got)
+		assert.Equal(t, domain.ErrNotFound, repo.Restore(ctx, date))
+		assert.Empty(t, dumpScales(t, repo))
+	})
+
+	t.Run("versions", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first := &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5}
+		assert.NoError(t, repo.Create(ctx, first))
+		assert.Equal(t, 1, first.Version)
+		second := &domain.Scale{Date: date, Min: 46, Max: 50, Difference: 4}
+		assert.NoError(t, repo.Create(ctx, second))
+		assert.Equal(t, 2, second.Version)
+		other := &domain.Scale{Date: date.AddDate(0, 0, 1), Min: 46, Max: 50, Difference: 4}
+		assert.NoError(t, repo.Create(ctx, other))
+		assert.Equal(t, 1, other.Version)
+
+		stale := &domain.Scale{Date: date, Min: 44, Max: 50, Version: 1}
+		assert.Equal(t, domain.ErrPreconditionFailed, repo.Update(ctx, stale))
+		current := &domain.Scale{Date: date, Min: 44, Max: 50, Version: 2}
+		assert.NoError(t, repo.Update(ctx, current))
+		assert.Equal(t, 3, current.Version)
+		unconditional := &domain.Scale{Date: date, Min: 43, Max: 50}
+		assert.NoError(t, repo.Update(ctx, unconditional))
+		assert.Equal(t, 4, unconditional.Version)
+		missing := &domain.Scale{Date: date.AddDate(0, 0, 2), Min: 43, Max: 50, Version: 1}
+		assert.Equal(t, domain.ErrPreconditionFailed, repo.Update(ctx, missing))
+
+		got, err := repo.GetScale(ctx, date)
+		assert.NoError(t, err)
+		assertScales(t, []domain.Scale{
+			{Date: date, Min: 43, Max: 50, Difference: 7, Version: 4},
+			{Date: date, Min: 43, Max: 50, Difference: 7, Version: 4},
+		}, got)
+
+		// versions keep increasing across deletes so a stale tag never matches again
+		assert.Equal(t, domain.ErrPreconditionFailed, repo.Delete(ctx, date, 3))
+		assert.NoError(t, repo.Delete(ctx, date, 4))
+		again := &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5}
+		assert.NoError(t, repo.Create(ctx, again))
+		assert.Equal(t, 6, again.Version)
+	})
+
+	t.Run("delete to trash", func(t *testing.T) {
+		deletedAt := now.AddDate(0, 0, -1)
+		repo := seed(t,
+			domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5, Version: 2},
+			domain.Scale{Date: date, Min: 46, Max: 50, Difference: 4, Version: 1, DeletedAt: &deletedAt},
+			domain.Scale{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3, Version: 1},
+		)
+
+		assert.NoError(t, repo.Delete(ctx, date, 2))
+		got, err := repo.GetScales(ctx)
+		assert.NoError(t, err)
+		assertScales(t, []domain.Scale{{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3, Version: 1}}, got)
+
+		// deleted readings are left untouched by updates
+		assert.NoError(t, repo.Update(ctx, &domain.Scale{Date: date, Min: 40, Max: 50}))
+		got, err = repo.GetTrash(ctx)
+		assert.NoError(t, err)
+		assertScales(t, []domain.Scale{
+			{Date: date, Min: 45, Max: 50, Difference: 5, Version: 3, DeletedAt: &now},
+			{Date: date, Min: 46, Max: 50, Difference: 4, Version: 1, DeletedAt: &deletedAt},
+		}, got)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		repo := seed(t,
+			domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5, Version: 2, DeletedAt: &old},
+			domain.Scale{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3, Version: 1},
+		)
+
+		assert.NoError(t, repo.Restore(ctx, date))
+		assert.Equal(t, domain.ErrNotFound, repo.Restore(ctx, date.AddDate(0, 0, 1)))
+
+		got, err := repo.GetScale(ctx, date)
+		assert.NoError(t, err)
+		assertScales(t, []domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5, Version: 3}}, got)
+	})
+
+	t.Run("restore next to a live reading", func(t *testing.T) {
+		live := domain.Scale{Date: date, Min: 46, Max: 50, Difference: 4, Version: 3}
+		repo := seed(t,
+			domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5, Version: 2, DeletedAt: &old},
+			live,
+		)
+
+		assert.Equal(t, domain.ErrConflict, repo.Restore(ctx, date))
+
+		got, err := repo.GetScale(ctx, date)
+		assert.NoError(t, err)
+		assertScales(t, []domain.Scale{live}, got)
+		got, err = repo.GetTrash(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, got, 1)
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		recent := now.AddDate(0, 0, -1)
+		repo := seed(t,
+			domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1, DeletedAt: &old},
+			domain.Scale{Date: date, Min: 46, Max: 50, Difference: 4, Version: 2, DeletedAt: &recent},
+			domain.Scale{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3, Version: 1, DeletedAt: &old},
+			domain.Scale{Date: date.AddDate(0, 0, 2), Min: 48, Max: 50, Difference: 2, Version: 1},
+		)
+
+		assert.NoError(t, repo.Purge(ctx, now.AddDate(0, 0, -30)))
+		assertScales(t, []domain.Scale{
+			{Date: date, Min: 46, Max: 50, Difference: 4, Version: 2, DeletedAt: &recent},
+			{Date: date.AddDate(0, 0, 2), Min: 48, Max: 50, Difference: 2, Version: 1},
+		}, dumpScales(t, repo))
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		repo := seed(t, domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1})
+
+		err := repo.Transaction(ctx, func(tx domain.ScaleRepository) error {
+			err := tx.Create(ctx, &domain.Scale{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3})
+			if err != nil {
+				return err
+			}
+			// the transaction sees its own writes
+			scales, err := tx.GetScales(ctx)
+			if err != nil {
+				return err
+			}
+			assert.Len(t, scales, 2)
+			return tx.Delete(ctx, date, 2)
+		})
+		assert.Equal(t, domain.ErrPreconditionFailed, err)
+		assertScales(t, []domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1}}, dumpScales(t, repo))
+
+		err = repo.Transaction(ctx, func(tx domain.ScaleRepository) error {
+			err := tx.Create(ctx, &domain.Scale{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3})
+			if err != nil {
+				return err
+			}
+			return tx.Delete(ctx, date, 1)
+		})
+		assert.NoError(t, err)
+		got, err := repo.GetScales(ctx)
+		assert.NoError(t, err)
+		assertScales(t, []domain.Scale{{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3, Version: 1}}, got)
+	})
+
+	t.Run("nested transaction", func(t *testing.T) {
+		repo := seed(t, domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1})
+
+		err := repo.Transaction(ctx, func(tx domain.ScaleRepository) error {
+			err := tx.Create(ctx, &domain.Scale{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3})
+			if err != nil {
+				return err
+			}
+			// the outer transaction carries on when the inner one fails
+			err = tx.Transaction(ctx, func(tx domain.ScaleRepository) error {
+				err := tx.Create(ctx, &domain.Scale{Date: date.AddDate(0, 0, 2), Min: 48, Max: 50, Difference: 2})
+				if err != nil {
+					return err
+				}
+				return errors.New("inner failed")
+			})
+			assert.EqualError(t, err, "inner failed")
+
+			return tx.Transaction(ctx, func(tx domain.ScaleRepository) error {
+				return tx.Update(ctx, &domain.Scale{Date: date, Min: 44, Max: 50, Difference: 6, Version: 1})
+			})
+		})
+		assert.NoError(t, err)
+		assertScales(t, []domain.Scale{
+			{Date: date, Min: 44, Max: 50, Difference: 6, Version: 2},
+			{Date: date.AddDate(0, 0, 1), Min: 47, Max: 50, Difference: 3, Version: 1},
+		}, dumpScales(t, repo))
+	})
+
+	t.Run("dump load", func(t *testing.T) {
+		scales := []domain.Scale{
+			{Date: date, Min: 45, Max: 50, Difference: 5, Version: 2, DeletedAt: &old},
+			{Date: date.AddDate(0, 0, 1), Min: 46, Max: 49, Difference: 3, Version: 1},
+		}
+		repo := seed(t, domain.Scale{Date: date.AddDate(0, 0, 5), Min: 45, Max: 50, Difference: 5, Version: 1})
+
+		assert.NoError(t, repo.Load(ctx, &domain.ScaleSnapshot{Scales: scales}))
+		scales[1].Min = 0
+		got := dumpScales(t, repo)
+		assert.Equal(t, 46, got[1].Min, "load keeps its own copy")
+		scales[1].Min = 46
+		assertScales(t, scales, got)
+
+		got[0].Min = 0
+		assert.Equal(t, 45, dumpScales(t, repo)[0].Min, "dump hands out a copy")
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		repo := newRepo(t)
+		done, cancel := context.WithCancel(ctx)
+		cancel()
+
+		assert.Equal(t, context.Canceled, repo.Create(done, &domain.Scale{Date: date, Min: 45, Max: 50}))
+		_, err := repo.GetScales(done)
+		assert.Equal(t, context.Canceled, err)
+		err = repo.Transaction(done, func(tx domain.ScaleRepository) error {
+			return errors.New("transaction ran after its context was done")
+		})
+		assert.Equal(t, context.Canceled, err)
+		assert.Empty(t, dumpScales(t, repo))
+
+		assert.Equal(t, context.Canceled, repo.Health(done))
+		assert.NoError(t, repo.Health(ctx))
+	})
+}
+
+func TestScaleRepositorySuite(t *testing.T) {
+	testScaleRepository(t, func(t *testing.T) domain.ScaleRepository {
+		return NewScaleRepository()
+	})
+}
+
+func TestFileScaleRepositorySuite(t *testing.T) {
+	testScaleRepository(t, func(t *testing.T) domain.ScaleRepository {
+		repo, err := NewFileScaleRepository(filepath.Join(t.TempDir(), "scales.log"), logrus.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo
+	})
+}
+
+func TestBoltScaleRepositorySuite(t *testing.T) {
+	testScaleRepository(t, func(t *testing.T) domain.ScaleRepository {
+		repo, err := NewBoltScaleRepository(filepath.Join(t.TempDir(), "scales.db"), logrus.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { repo.(*boltScaleRepository).Close() })
+		return repo
+	})
+}
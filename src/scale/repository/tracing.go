@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/scale/src/scale/repository"
+
+type tracingScaleRepository struct {
+	domain.ScaleRepository
+	tracer trace.Tracer
+	// the transaction span, the context given to tx operations is the caller's
+	transaction trace.Span
+}
+
+// NewTracingScaleRepository wraps any ScaleRepository, starting a span for
+// every operation with the global tracer provider. Health is left out so
+// readiness probes do not flood the traces.
+func NewTracingScaleRepository(scaleRepository domain.ScaleRepository) domain.ScaleRepository {
+	return &tracingScaleRepository{
+		ScaleRepository: scaleRepository,
+		tracer:          otel.Tracer(instrumentationName),
+	}
+}
+
+func (t *tracingScaleRepository) start(ctx context.Context, operation string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	if t.transaction != nil {
+		ctx = trace.ContextWithSpan(ctx, t.transaction)
+	}
+	return t.tracer.Start(ctx, "ScaleRepository."+operation, trace.WithAttributes(attributes...))
+}
+
+func (t *tracingScaleRepository) Create(ctx context.Context, param *domain.Scale) (err error) {
+	ctx, span := t.start(ctx, "Create", attribute.String("scale.date", param.Date.Format(common.TimeLayout)))
+	defer func() { helper.EndSpan(span, err) }()
+
+	err = t.ScaleRepository.Create(ctx, param)
+	span.SetAttributes(attribute.Int("scale.version", param.Version))
+	return err
+}
+
+func (t *tracingScaleRepository) GetScales(ctx context.Context) (scales []domain.Scale, err error) {
+	ctx, span := t.start(ctx, "GetScales")
+	defer func() { helper.EndSpan(span, err) }()
+
+	scales, err = t.ScaleRepository.GetScales(ctx)
+	span.SetAttributes(attribute.Int("scale.count", len(scales)))
+	return scales, err
+}
+
+func (t *tracingScaleRepository) GetScale(ctx context.Context, date time.Time) (scales []domain.Scale, err error) {
+	ctx, span := t.start(ctx, "GetScale", attribute.String("scale.date", date.Format(common.TimeLayout)))
+	defer func() { helper.EndSpan(span, err) }()
+
+	scales, err = t.ScaleRepository.GetScale(ctx, date)
+	span.SetAttributes(attribute.Int("scale.count", len(scales)))
+	return scales, err
+}
+
+func (t *tracingScaleRepository) Update(ctx context.Context, param *domain.Scale) (err error) {
+	ctx, span := t.start(ctx, "Update", attribute.String("scale.date", param.Date.Format(common.TimeLayout)))
+	defer func() { helper.EndSpan(span, err) }()
+
+	err = t.ScaleRepository.Update(ctx, param)
+	span.SetAttributes(attribute.Int("scale.version", param.Version))
+	return err
+}
+
+func (t *tracingScaleRepository) Delete(ctx context.Context, date time.Time, version int) (err error) {
+	ctx, span := t.start(ctx, "Delete",
+		attribute.String("scale.date", date.Format(common.TimeLayout)),
+		attribute.Int("scale.version", version),
+	)
+	defer func() { helper.EndSpan(span, err) }()
+
+	return t.ScaleRepository.Delete(ctx, date, version)
+}
+
+func (t *tracingScaleRepository) GetTrash(ctx context.Context) (scales []domain.Scale, err error) {
+	ctx, span := t.start(ctx, "GetTrash")
+	defer func() { helper.EndSpan(span, err) }()
+
+	scales, err = t.ScaleRepository.GetTrash(ctx)
+	span.SetAttributes(attribute.Int("scale.count", len(scales)))
+	return scales, err
+}
+
+func (t *tracingScaleRepository) Restore(ctx context.Context, date time.Time) (err error) {
+	ctx, span := t.start(ctx, "Restore", attribute.String("scale.date", date.Format(common.TimeLayout)))
+	defer func() { helper.EndSpan(span, err) }()
+
+	return t.ScaleRepository.Restore(ctx, date)
+}
+
+func (t *tracingScaleRepository) Purge(ctx context.Context, before time.Time) (err error) {
+	ctx, span := t.start(ctx, "Purge", attribute.String("scale.before", before.Format(time.RFC3339)))
+	defer func() { helper.EndSpan(span, err) }()
+
+	return t.ScaleRepository.Purge(ctx, before)
+}
+
+func (t *tracingScaleRepository) Dump(ctx context.Context) (snapshot *domain.ScaleSnapshot, err error) {
+	ctx, span := t.start(ctx, "Dump")
+	defer func() { helper.EndSpan(span, err) }()
+
+	return t.ScaleRepository.Dump(ctx)
+}
+
+func (t *tracingScaleRepository) Load(ctx context.Context, snapshot *domain.ScaleSnapshot) (err error) {
+	ctx, span := t.start(ctx, "Load", attribute.Int("scale.count", len(snapshot.Scales)))
+	defer func() { helper.EndSpan(span, err) }()
+
+	return t.ScaleRepository.Load(ctx, snapshot)
+}
+
+// Transaction traces the operations of fn as its children.
+func (t *tracingScaleRepository) Transaction(ctx context.Context, fn func(tx domain.ScaleRepository) error) (err error) {
+	ctx, span := t.start(ctx, "Transaction")
+	defer func() { helper.EndSpan(span, err) }()
+
+	return t.ScaleRepository.Transaction(ctx, func(tx domain.ScaleRepository) error {
+		return fn(&tracingScaleRepository{
+			ScaleRepository: tx,
+			tracer:          t.tracer,
+			transaction:     span,
+		})
+	})
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	repo := NewTracingScaleRepository(&scaleRepository{
+		scales: []domain.Scale{
+			{Date: date, Min: 45, Max: 50, Difference: 5, Version: 1},
+		},
+	})
+
+	scales, err := repo.GetScale(context.Background(), date)
+	assert.NoError(t, err)
+	assert.Len(t, scales, 1)
+
+	err = repo.Transaction(context.Background(), func(tx domain.ScaleRepository) error {
+		return tx.Delete(context.Background(), date, 2)
+	})
+	assert.Equal(t, domain.ErrPreconditionFailed, err)
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 3) {
+		return
+	}
+
+	get := spans[0]
+	assert.Equal(t, "ScaleRepository.GetScale", get.Name())
+	assert.Contains(t, get.Attributes(), attribute.String("scale.date", "2022-02-01"))
+	assert.Contains(t, get.Attributes(), attribute.Int("scale.count", 1))
+	assert.Equal(t, codes.Unset, get.Status().Code)
+
+	del, transaction := spans[1], spans[2]
+	assert.Equal(t, "ScaleRepository.Delete", del.Name())
+	assert.Equal(t, "ScaleRepository.Transaction", transaction.Name())
+	assert.Equal(t, transaction.SpanContext().SpanID(), del.Parent().SpanID())
+	assert.Equal(t, codes.Error, del.Status().Code)
+	assert.Equal(t, codes.Error, transaction.Status().Code)
+}
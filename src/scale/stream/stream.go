@@ -0,0 +1,111 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/scale/src/domain"
+)
+
+const (
+	DefaultLogSize = 256
+	bufferSize     = 64
+)
+
+type scaleStream struct {
+	mu          sync.Mutex
+	lastID      uint64
+	log         []domain.ScaleStreamEvent
+	logSize     int
+	subscribers map[*domain.ScaleSubscription]chan domain.ScaleStreamEvent
+	closed      bool
+}
+
+func NewScaleStream(logSize int) domain.ScaleStream {
+	return &scaleStream{
+		logSize:     logSize,
+		subscribers: map[*domain.ScaleSubscription]chan domain.ScaleStreamEvent{},
+	}
+}
+
+// Publish never blocks the writer, a subscriber whose buffer is full is
+// dropped and can resume from the log with its last event id.
+func (s *scaleStream) Publish(event domain.ScaleEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	streamEvent := domain.ScaleStreamEvent{
+		ID:         s.lastID,
+		ScaleEvent: event,
+	}
+	s.log = append(s.log, streamEvent)
+	if len(s.log) > s.logSize {
+		s.log = s.log[len(s.log)-s.logSize:]
+	}
+
+	for subscription, events := range s.subscribers {
+		select {
+		case events <- streamEvent:
+		default:
+			delete(s.subscribers, subscription)
+			close(events)
+		}
+	}
+}
+
+func (s *scaleStream) Subscribe(lastEventID uint64) *domain.ScaleSubscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make(chan domain.ScaleStreamEvent, bufferSize)
+	subscription := &domain.ScaleSubscription{
+		Backlog: []domain.ScaleStreamEvent{},
+		Events:  events,
+	}
+	if s.closed {
+		subscription.Closed = true
+		close(events)
+		return subscription
+	}
+	s.subscribers[subscription] = events
+
+	if lastEventID == 0 {
+		return subscription
+	}
+	// an id ahead of the log comes from before a restart
+	if lastEventID > s.lastID || (len(s.log) > 0 && s.log[0].ID > lastEventID+1) {
+		subscription.Missed = true
+	}
+	for _, event := range s.log {
+		if event.ID > lastEventID {
+			subscription.Backlog = append(subscription.Backlog, event)
+		}
+	}
+	return subscription
+}
+
+func (s *scaleStream) Unsubscribe(subscription *domain.ScaleSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events, ok := s.subscribers[subscription]
+	if !ok {
+		return
+	}
+	delete(s.subscribers, subscription)
+	close(events)
+}
+
+// Close ends every subscription and those made afterwards, so the streams
+// they serve return instead of holding a shutdown up.
+func (s *scaleStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for subscription, events := range s.subscribers {
+		delete(s.subscribers, subscription)
+		subscription.Closed = true
+		close(events)
+	}
+}
@@ -0,0 +1,136 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/scale/src/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func ids(events []domain.ScaleStreamEvent) []uint64 {
+	result := []uint64{}
+	for _, event := range events {
+		result = append(result, event.ID)
+	}
+	return result
+}
+
+func TestPublish(t *testing.T) {
+	s := NewScaleStream(DefaultLogSize)
+	subscription := s.Subscribe(0)
+
+	s.Publish(domain.ScaleEvent{Type: domain.ScaleCreated})
+	s.Publish(domain.ScaleEvent{Type: domain.ScaleDeleted})
+
+	first := <-subscription.Events
+	second := <-subscription.Events
+	assert.Equal(t, uint64(1), first.ID)
+	assert.Equal(t, domain.ScaleCreated, first.Type)
+	assert.Equal(t, uint64(2), second.ID)
+	assert.Equal(t, domain.ScaleDeleted, second.Type)
+}
+
+func TestPublishSlowSubscriber(t *testing.T) {
+	s := NewScaleStream(DefaultLogSize)
+	slow := s.Subscribe(0)
+
+	for i := 0; i < bufferSize+1; i++ {
+		s.Publish(domain.ScaleEvent{Type: domain.ScaleCreated})
+	}
+
+	received := 0
+	for range slow.Events {
+		received++
+	}
+	assert.Equal(t, bufferSize, received)
+
+	// resuming from the log picks up what the buffer could not hold
+	resumed := s.Subscribe(uint64(received))
+	assert.False(t, resumed.Missed)
+	assert.Equal(t, []uint64{bufferSize + 1}, ids(resumed.Backlog))
+}
+
+func TestSubscribe(t *testing.T) {
+	s := NewScaleStream(3)
+	for i := 0; i < 5; i++ {
+		s.Publish(domain.ScaleEvent{Type: domain.ScaleUpdated})
+	}
+
+	tests := []struct {
+		name        string
+		args        uint64
+		wantBacklog []uint64
+		wantMissed  bool
+	}{
+		{
+			name:        "live only",
+			args:        0,
+			wantBacklog: []uint64{},
+		},
+		{
+			name:        "resume",
+			args:        3,
+			wantBacklog: []uint64{4, 5},
+		},
+		{
+			name:        "resume oldest",
+			args:        2,
+			wantBacklog: []uint64{3, 4, 5},
+		},
+		{
+			name:        "up to date",
+			args:        5,
+			wantBacklog: []uint64{},
+		},
+		{
+			name:        "left the log",
+			args:        1,
+			wantBacklog: []uint64{3, 4, 5},
+			wantMissed:  true,
+		},
+		{
+			name:        "before restart",
+			args:        9,
+			wantBacklog: []uint64{},
+			wantMissed:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			subscription := s.Subscribe(test.args)
+			defer s.Unsubscribe(subscription)
+
+			assert.Equal(t, test.wantBacklog, ids(subscription.Backlog))
+			assert.Equal(t, test.wantMissed, subscription.Missed)
+		})
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	s := NewScaleStream(DefaultLogSize)
+	subscription := s.Subscribe(0)
+
+	s.Unsubscribe(subscription)
+	s.Unsubscribe(subscription)
+	s.Publish(domain.ScaleEvent{Type: domain.ScaleCreated})
+
+	_, ok := <-subscription.Events
+	assert.False(t, ok)
+}
+
+func TestClose(t *testing.T) {
+	s := NewScaleStream(DefaultLogSize)
+	subscription := s.Subscribe(0)
+
+	s.Close()
+	s.Publish(domain.ScaleEvent{Type: domain.ScaleCreated})
+
+	_, ok := <-subscription.Events
+	assert.False(t, ok)
+	assert.True(t, subscription.Closed)
+
+	late := s.Subscribe(0)
+	_, ok = <-late.Events
+	assert.False(t, ok)
+	assert.True(t, late.Closed)
+}
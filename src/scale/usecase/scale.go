@@ -1,44 +1,152 @@
 package usecase
 
 import (
-	"errors"
+	"context"
 	"math"
 	"time"
 
 	"github.com/scale/src/common"
 	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/sirupsen/logrus"
 )
 
 type scaleUsecase struct {
-	scaleRepository domain.ScaleRepository
+	scaleRepository        domain.ScaleRepository
+	scaleHistoryRepository domain.ScaleHistoryRepository
+	publishers             []domain.ScalePublisher
+	// for calls whose context carries no logger of its own
+	logger logrus.FieldLogger
 }
 
-func NewScaleUsecase(scaleRepository domain.ScaleRepository) domain.ScaleUsecase {
+func NewScaleUsecase(scaleRepository domain.ScaleRepository, scaleHistoryRepository domain.ScaleHistoryRepository, logger logrus.FieldLogger, publishers ...domain.ScalePublisher) domain.ScaleUsecase {
 	return &scaleUsecase{
-		scaleRepository: scaleRepository,
+		scaleRepository:        scaleRepository,
+		scaleHistoryRepository: scaleHistoryRepository,
+		publishers:             publishers,
+		logger:                 logger,
 	}
 }
 
-func (s *scaleUsecase) Create(param *domain.Scale) error {
+func (s *scaleUsecase) publish(ctx context.Context, eventType string, scale domain.Scale) {
+	event := domain.ScaleEvent{
+		Type:       eventType,
+		Scale:      scale,
+		OccurredAt: helper.Now(),
+	}
+	helper.GetLoggerOr(ctx, s.logger).WithFields(logrus.Fields{
+		"event":   eventType,
+		"date":    scale.Date.Format(common.TimeLayout),
+		"version": scale.Version,
+	}).Debug("scale changed")
+	for _, publisher := range s.publishers {
+		publisher.Publish(event)
+	}
+}
+
+func create(ctx context.Context, scaleRepository domain.ScaleRepository, param *domain.Scale) error {
+	if param.Max < param.Min {
+		return domain.ErrMinAboveMax
+	}
+	param.Difference = param.Max - param.Min
+
+	return scaleRepository.Create(ctx, param)
+}
+
+func update(ctx context.Context, scaleRepository domain.ScaleRepository, param *domain.Scale) error {
 	if param.Max < param.Min {
-		return errors.New("Min. greater than max.")
+		return domain.ErrMinAboveMax
 	}
 	param.Difference = param.Max - param.Min
 
-	err := s.scaleRepository.Create(param)
+	return scaleRepository.Update(ctx, param)
+}
+
+func (s *scaleUsecase) Create(ctx context.Context, param *domain.Scale) error {
+	err := create(ctx, s.scaleRepository, param)
 	if err != nil {
 		return err
 	}
+
+	s.publish(ctx, domain.ScaleCreated, *param)
 	return nil
 }
 
-func (s *scaleUsecase) GetScales() (*domain.ScaleResponse, error) {
-	scales, err := s.scaleRepository.GetScales()
+func (s *scaleUsecase) GetScales(ctx context.Context) (*domain.ScaleResponse, error) {
+	scales, err := s.scaleRepository.GetScales(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ScaleResponse{
+		Scales:  scales,
+		Average: average(scales),
+	}, nil
+}
+
+// GetScalesInRange returns the readings newest first within the inclusive
+// from and to dates, either may be empty to leave that end open.
+func (s *scaleUsecase) GetScalesInRange(ctx context.Context, from, to string) ([]domain.Scale, error) {
+	var bounds [2]string
+	for i, value := range []string{from, to} {
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse(common.TimeLayout, value)
+		if err != nil {
+			return nil, err
+		}
+		bounds[i] = date.Format(common.TimeLayout)
+	}
+
+	scales, err := s.scaleRepository.GetScales(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []domain.Scale{}
+	for _, scale := range scales {
+		day := scale.Date.Format(common.TimeLayout)
+		if (bounds[0] != "" && day < bounds[0]) || (bounds[1] != "" && day > bounds[1]) {
+			continue
+		}
+		result = append(result, scale)
+	}
+	return result, nil
+}
+
+func (s *scaleUsecase) GetSummary(ctx context.Context, from, to string) (*domain.ScaleSummary, error) {
+	scales, err := s.GetScalesInRange(ctx, from, to)
 	if err != nil {
 		return nil, err
 	}
-	scaleResponse := &domain.ScaleResponse{
-		Scales: scales,
+
+	summary := &domain.ScaleSummary{
+		Count:   len(scales),
+		Average: average(scales),
+	}
+	if len(scales) == 0 {
+		return summary, nil
+	}
+
+	first, last := scales[len(scales)-1].Date, scales[0].Date
+	lowest, highest := scales[0].Min, scales[0].Max
+	for _, scale := range scales {
+		if scale.Min < lowest {
+			lowest = scale.Min
+		}
+		if scale.Max > highest {
+			highest = scale.Max
+		}
+	}
+	summary.First, summary.Last = &first, &last
+	summary.Lowest, summary.Highest = &lowest, &highest
+	return summary, nil
+}
+
+// average rounds to one decimal and is nil without scales.
+func average(scales []domain.Scale) *domain.ScaleAverrage {
+	if len(scales) == 0 {
+		return nil
 	}
 
 	var minTotal int
@@ -51,22 +159,19 @@ func (s *scaleUsecase) GetScales() (*domain.ScaleResponse, error) {
 	avgMin := float64(minTotal) / float64(len(scales))
 	avgMax := float64(maxTotal) / float64(len(scales))
 	diff := avgMax - avgMin
-	avg := &domain.ScaleAverrage{
+	return &domain.ScaleAverrage{
 		Min:        math.Round(avgMin*10) / 10,
 		Max:        math.Round(avgMax*10) / 10,
 		Difference: math.Round(diff*10) / 10,
 	}
-	scaleResponse.Average = avg
-
-	return scaleResponse, err
 }
 
-func (s *scaleUsecase) GetScale(date string) ([]domain.Scale, error) {
+func (s *scaleUsecase) GetScale(ctx context.Context, date string) ([]domain.Scale, error) {
 	d, err := time.Parse(common.TimeLayout, date)
 	if err != nil {
 		return nil, err
 	}
-	scales, err := s.scaleRepository.GetScale(d)
+	scales, err := s.scaleRepository.GetScale(ctx, d)
 	if err != nil {
 		return nil, err
 	}
@@ -74,28 +179,154 @@ func (s *scaleUsecase) GetScale(date string) ([]domain.Scale, error) {
 	return scales, err
 }
 
-func (s *scaleUsecase) Update(param *domain.Scale) error {
-	if param.Max < param.Min {
-		return errors.New("Min. greater than max.")
+func (s *scaleUsecase) Update(ctx context.Context, param *domain.Scale) error {
+	err := update(ctx, s.scaleRepository, param)
+	if err != nil {
+		return err
 	}
-	param.Difference = param.Max - param.Min
 
-	err := s.scaleRepository.Update(param)
+	s.publish(ctx, domain.ScaleUpdated, *param)
+	return nil
+}
+
+func (s *scaleUsecase) Delete(ctx context.Context, date string, version int) error {
+	d, err := time.Parse(common.TimeLayout, date)
+	if err != nil {
+		return err
+	}
+	err = s.scaleRepository.Delete(ctx, d, version)
 	if err != nil {
 		return err
 	}
 
+	s.publish(ctx, domain.ScaleDeleted, domain.Scale{Date: d})
 	return nil
 }
 
-func (s *scaleUsecase) Delete(date string) error {
+var operationEvents = map[string]string{
+	domain.ScaleOperationCreate: domain.ScaleCreated,
+	domain.ScaleOperationUpdate: domain.ScaleUpdated,
+	domain.ScaleOperationDelete: domain.ScaleDeleted,
+}
+
+func applyOperation(ctx context.Context, tx domain.ScaleRepository, operation domain.ScaleOperation) (*domain.Scale, error) {
+	date, err := time.Parse(common.TimeLayout, operation.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := &domain.Scale{
+		Date:    date,
+		Min:     operation.Min,
+		Max:     operation.Max,
+		Version: operation.Version,
+	}
+	switch operation.Op {
+	case domain.ScaleOperationCreate:
+		scale.Version = 0
+		err = create(ctx, tx, scale)
+	case domain.ScaleOperationUpdate:
+		err = update(ctx, tx, scale)
+	case domain.ScaleOperationDelete:
+		scale = &domain.Scale{Date: date}
+		err = tx.Delete(ctx, date, operation.Version)
+	default:
+		err = domain.ErrBadParamInput
+	}
+	if err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+// Batch applies every operation or none of them. On failure the result of the
+// failed operation holds its error and no result holds a scale.
+func (s *scaleUsecase) Batch(ctx context.Context, operations []domain.ScaleOperation) ([]domain.ScaleOperationResult, error) {
+	results := make([]domain.ScaleOperationResult, len(operations))
+	for i, operation := range operations {
+		results[i] = domain.ScaleOperationResult{
+			Op:   operation.Op,
+			Date: operation.Date,
+		}
+	}
+
+	err := s.scaleRepository.Transaction(ctx, func(tx domain.ScaleRepository) error {
+		for i, operation := range operations {
+			scale, err := applyOperation(ctx, tx, operation)
+			if err != nil {
+				helper.GetLoggerOr(ctx, s.logger).WithFields(logrus.Fields{
+					"operation": i,
+					"op":        operation.Op,
+					"date":      operation.Date,
+				}).WithError(err).Warn("batch rolled back")
+				results[i].Error = err.Error()
+				if _, ok := err.(*time.ParseError); ok {
+					err = domain.ErrBadParamInput
+				}
+				return err
+			}
+			results[i].Scale = scale
+		}
+		return nil
+	})
+	if err != nil {
+		for i := range results {
+			results[i].Scale = nil
+		}
+		return results, err
+	}
+
+	for _, result := range results {
+		s.publish(ctx, operationEvents[result.Op], *result.Scale)
+	}
+	return results, nil
+}
+
+func (s *scaleUsecase) GetTrash(ctx context.Context) ([]domain.Scale, error) {
+	return s.scaleRepository.GetTrash(ctx)
+}
+
+func (s *scaleUsecase) Restore(ctx context.Context, date string) error {
 	d, err := time.Parse(common.TimeLayout, date)
 	if err != nil {
 		return err
 	}
-	err = s.scaleRepository.Delete(d)
+	err = s.scaleRepository.Restore(ctx, d)
+	if err != nil {
+		return err
+	}
+
+	scales, err := s.scaleRepository.GetScale(ctx, d)
 	if err != nil {
 		return err
 	}
+	for _, scale := range scales {
+		s.publish(ctx, domain.ScaleRestored, scale)
+	}
 	return nil
 }
+
+// Purge permanently removes the readings deleted longer than retention ago,
+// retention must be positive.
+func (s *scaleUsecase) Purge(ctx context.Context, retention time.Duration) error {
+	if retention <= 0 {
+		return domain.ErrBadParamInput
+	}
+	before := helper.Now().Add(-retention)
+	err := s.scaleRepository.Purge(ctx, before)
+	if err != nil {
+		return err
+	}
+
+	helper.GetLoggerOr(ctx, s.logger).WithField("before", before).Info("trash purged")
+	return nil
+}
+
+func (s *scaleUsecase) GetHistory(ctx context.Context, date string) ([]domain.ScaleHistory, error) {
+	d, err := time.Parse(common.TimeLayout, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scaleHistoryRepository.GetHistory(d)
+}
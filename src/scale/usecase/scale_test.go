@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/scale/src/domain"
 	"github.com/scale/src/helper"
 	mock_domain "github.com/scale/src/mock"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,7 +20,7 @@ func init() {
 }
 
 func TestNewScaleUsecase(t *testing.T) {
-	NewScaleUsecase(nil)
+	NewScaleUsecase(nil, nil, nil)
 }
 
 func TestCreate(t *testing.T) {
@@ -52,7 +54,7 @@ func TestCreate(t *testing.T) {
 			},
 			wantErr: false,
 			mock: func() {
-				scaleMock.EXPECT().Create(&domain.Scale{
+				scaleMock.EXPECT().Create(gomock.Any(), &domain.Scale{
 					Date:       date,
 					Min:        45,
 					Max:        50,
@@ -71,7 +73,7 @@ func TestCreate(t *testing.T) {
 			},
 			wantErr: true,
 			mock: func() {
-				scaleMock.EXPECT().Create(&domain.Scale{
+				scaleMock.EXPECT().Create(gomock.Any(), &domain.Scale{
 					Date:       date,
 					Min:        45,
 					Max:        50,
@@ -96,7 +98,7 @@ func TestCreate(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mock()
 
-			err := uc.Create(test.args.param)
+			err := uc.Create(context.Background(), test.args.param)
 			assert.Equal(t, test.wantErr, err != nil)
 		})
 	}
@@ -147,7 +149,7 @@ func TestGetScales(t *testing.T) {
 			},
 			wantErr: false,
 			mock: func() {
-				scaleMock.EXPECT().GetScales().Return([]domain.Scale{
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return([]domain.Scale{
 					{
 						Date:       date,
 						Min:        45,
@@ -168,7 +170,7 @@ func TestGetScales(t *testing.T) {
 			wantResult: nil,
 			wantErr:    true,
 			mock: func() {
-				scaleMock.EXPECT().GetScales().Return(nil, errors.New("some error"))
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(nil, errors.New("some error"))
 			},
 		},
 	}
@@ -177,7 +179,7 @@ func TestGetScales(t *testing.T) {
 			t.Run(test.name, func(t *testing.T) {
 				test.mock()
 
-				got, err := uc.GetScales()
+				got, err := uc.GetScales(context.Background())
 				assert.Equal(t, test.wantErr, err != nil)
 				assert.Equal(t, test.wantResult, got)
 			})
@@ -185,6 +187,152 @@ func TestGetScales(t *testing.T) {
 	}
 }
 
+func TestGetScalesInRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	scales := []domain.Scale{
+		{Date: date.AddDate(0, 0, 2), Min: 48, Max: 50, Difference: 2},
+		{Date: date.AddDate(0, 0, 1), Min: 47, Max: 51, Difference: 4},
+		{Date: date, Min: 47, Max: 50, Difference: 3},
+	}
+
+	scaleMock := mock_domain.NewMockScaleRepository(ctrl)
+
+	uc := &scaleUsecase{
+		scaleRepository: scaleMock,
+	}
+
+	type args struct {
+		from string
+		to   string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantResult []domain.Scale
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name:       "open range",
+			wantResult: scales,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(scales, nil)
+			},
+		},
+		{
+			name:       "inclusive range",
+			args:       args{from: "2022-02-01", to: "2022-02-02"},
+			wantResult: scales[1:],
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(scales, nil)
+			},
+		},
+		{
+			name:       "empty range",
+			args:       args{from: "2022-03-01"},
+			wantResult: []domain.Scale{},
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(scales, nil)
+			},
+		},
+		{
+			name:    "invalid date",
+			args:    args{to: "date"},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name:    "error",
+			wantErr: true,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+
+			got, err := uc.GetScalesInRange(context.Background(), test.args.from, test.args.to)
+			assert.Equal(t, test.wantErr, err != nil)
+			assert.Equal(t, test.wantResult, got)
+		})
+	}
+}
+
+func TestGetSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+	scales := []domain.Scale{
+		{Date: date.AddDate(0, 0, 2), Min: 48, Max: 50, Difference: 2},
+		{Date: date.AddDate(0, 0, 1), Min: 47, Max: 51, Difference: 4},
+		{Date: date, Min: 46, Max: 50, Difference: 4},
+	}
+	first, last := date.AddDate(0, 0, 1), date.AddDate(0, 0, 2)
+	lowest, highest := 47, 51
+
+	scaleMock := mock_domain.NewMockScaleRepository(ctrl)
+
+	uc := &scaleUsecase{
+		scaleRepository: scaleMock,
+	}
+
+	tests := []struct {
+		name       string
+		from       string
+		wantResult *domain.ScaleSummary
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success",
+			from: "2022-02-02",
+			wantResult: &domain.ScaleSummary{
+				Count:   2,
+				First:   &first,
+				Last:    &last,
+				Lowest:  &lowest,
+				Highest: &highest,
+				Average: &domain.ScaleAverrage{
+					Min:        47.5,
+					Max:        50.5,
+					Difference: 3,
+				},
+			},
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(scales, nil)
+			},
+		},
+		{
+			name:       "without readings",
+			from:       "2022-03-01",
+			wantResult: &domain.ScaleSummary{},
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(scales, nil)
+			},
+		},
+		{
+			name:    "error",
+			wantErr: true,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+
+			got, err := uc.GetSummary(context.Background(), test.from, "")
+			assert.Equal(t, test.wantErr, err != nil)
+			assert.Equal(t, test.wantResult, got)
+		})
+	}
+}
+
 func TestGetScale(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -221,7 +369,7 @@ func TestGetScale(t *testing.T) {
 			},
 			wantErr: false,
 			mock: func() {
-				scaleMock.EXPECT().GetScale(date).Return([]domain.Scale{
+				scaleMock.EXPECT().GetScale(gomock.Any(), date).Return([]domain.Scale{
 					{
 						Date:       date,
 						Min:        45,
@@ -239,7 +387,7 @@ func TestGetScale(t *testing.T) {
 			wantResult: nil,
 			wantErr:    true,
 			mock: func() {
-				scaleMock.EXPECT().GetScale(date).Return(nil, errors.New("some error"))
+				scaleMock.EXPECT().GetScale(gomock.Any(), date).Return(nil, errors.New("some error"))
 			},
 		},
 		{
@@ -256,7 +404,7 @@ func TestGetScale(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			test.mock()
-			got, err := uc.GetScale(test.args.date)
+			got, err := uc.GetScale(context.Background(), test.args.date)
 			assert.Equal(t, test.wantErr, err != nil)
 			assert.Equal(t, test.wantResult, got)
 		})
@@ -295,7 +443,7 @@ func TestUpdate(t *testing.T) {
 			},
 			wantErr: false,
 			mock: func() {
-				scaleMock.EXPECT().Update(&domain.Scale{
+				scaleMock.EXPECT().Update(gomock.Any(), &domain.Scale{
 					Date:       date,
 					Min:        47,
 					Max:        50,
@@ -315,7 +463,7 @@ func TestUpdate(t *testing.T) {
 			},
 			wantErr: true,
 			mock: func() {
-				scaleMock.EXPECT().Update(&domain.Scale{
+				scaleMock.EXPECT().Update(gomock.Any(), &domain.Scale{
 					Date:       date,
 					Min:        47,
 					Max:        50,
@@ -339,7 +487,7 @@ func TestUpdate(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			test.mock()
-			err := uc.Update(test.args.param)
+			err := uc.Update(context.Background(), test.args.param)
 			assert.Equal(t, test.wantErr, err != nil)
 		})
 	}
@@ -372,7 +520,7 @@ func TestDelete(t *testing.T) {
 			},
 			wantErr: false,
 			mock: func() {
-				scaleMock.EXPECT().Delete(date).Return(nil)
+				scaleMock.EXPECT().Delete(gomock.Any(), date, 0).Return(nil)
 			},
 		},
 		{
@@ -382,7 +530,7 @@ func TestDelete(t *testing.T) {
 			},
 			wantErr: true,
 			mock: func() {
-				scaleMock.EXPECT().Delete(date).Return(errors.New("some error"))
+				scaleMock.EXPECT().Delete(gomock.Any(), date, 0).Return(errors.New("some error"))
 			},
 		},
 		{
@@ -397,8 +545,326 @@ func TestDelete(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			test.mock()
-			err := uc.Delete(test.args.date)
+			err := uc.Delete(context.Background(), test.args.date, 0)
+			assert.Equal(t, test.wantErr, err != nil)
+		})
+	}
+}
+
+func TestBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	date, _ := time.Parse(common.TimeLayout, "2022-02-01")
+	now := time.Date(2022, 2, 1, 7, 0, 0, 0, helper.GetLocation())
+	original := helper.Now
+	helper.Now = func() time.Time { return now }
+	defer func() { helper.Now = original }()
+
+	scaleMock := mock_domain.NewMockScaleRepository(ctrl)
+	publisherMock := mock_domain.NewMockScalePublisher(ctrl)
+
+	uc := NewScaleUsecase(scaleMock, nil, logrus.New(), publisherMock)
+	transaction := func(ctx context.Context, fn func(tx domain.ScaleRepository) error) error {
+		return fn(scaleMock)
+	}
+	operations := []domain.ScaleOperation{
+		{Op: domain.ScaleOperationCreate, Date: "2022-02-01", Min: 45, Max: 50, Version: 3},
+		{Op: domain.ScaleOperationDelete, Date: "2022-02-02", Version: 1},
+	}
+
+	tests := []struct {
+		name       string
+		args       []domain.ScaleOperation
+		wantResult []domain.ScaleOperationResult
+		wantErr    error
+		mock       func()
+	}{
+		{
+			name: "success",
+			args: operations,
+			wantResult: []domain.ScaleOperationResult{
+				{Op: domain.ScaleOperationCreate, Date: "2022-02-01", Scale: &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5}},
+				{Op: domain.ScaleOperationDelete, Date: "2022-02-02", Scale: &domain.Scale{Date: date.AddDate(0, 0, 1)}},
+			},
+			mock: func() {
+				scaleMock.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(transaction)
+				scaleMock.EXPECT().Create(gomock.Any(), &domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5}).Return(nil)
+				scaleMock.EXPECT().Delete(gomock.Any(), date.AddDate(0, 0, 1), 1).Return(nil)
+				publisherMock.EXPECT().Publish(domain.ScaleEvent{
+					Type:       domain.ScaleCreated,
+					Scale:      domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5},
+					OccurredAt: now,
+				})
+				publisherMock.EXPECT().Publish(domain.ScaleEvent{
+					Type:       domain.ScaleDeleted,
+					Scale:      domain.Scale{Date: date.AddDate(0, 0, 1)},
+					OccurredAt: now,
+				})
+			},
+		},
+		{
+			name: "rollback",
+			args: operations,
+			wantResult: []domain.ScaleOperationResult{
+				{Op: domain.ScaleOperationCreate, Date: "2022-02-01"},
+				{Op: domain.ScaleOperationDelete, Date: "2022-02-02", Error: domain.ErrPreconditionFailed.Error()},
+			},
+			wantErr: domain.ErrPreconditionFailed,
+			mock: func() {
+				scaleMock.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(transaction)
+				scaleMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				scaleMock.EXPECT().Delete(gomock.Any(), date.AddDate(0, 0, 1), 1).Return(domain.ErrPreconditionFailed)
+			},
+		},
+		{
+			name: "invalid date",
+			args: []domain.ScaleOperation{
+				{Op: domain.ScaleOperationUpdate, Date: "date", Min: 45, Max: 50},
+			},
+			wantResult: []domain.ScaleOperationResult{
+				{Op: domain.ScaleOperationUpdate, Date: "date", Error: `parsing time "date" as "2006-01-02": cannot parse "date" as "2006"`},
+			},
+			wantErr: domain.ErrBadParamInput,
+			mock: func() {
+				scaleMock.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(transaction)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+			got, err := uc.Batch(context.Background(), test.args)
+			assert.Equal(t, test.wantErr, err)
+			assert.Equal(t, test.wantResult, got)
+		})
+	}
+}
+
+func TestGetTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, helper.GetLocation())
+
+	scaleMock := mock_domain.NewMockScaleRepository(ctrl)
+
+	uc := &scaleUsecase{
+		scaleRepository: scaleMock,
+	}
+
+	scaleMock.EXPECT().GetTrash(gomock.Any()).Return([]domain.Scale{{Date: date, DeletedAt: &date}}, nil)
+
+	got, err := uc.GetTrash(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Scale{{Date: date, DeletedAt: &date}}, got)
+}
+
+func TestRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	scaleMock := mock_domain.NewMockScaleRepository(ctrl)
+	publisherMock := mock_domain.NewMockScalePublisher(ctrl)
+
+	uc := &scaleUsecase{
+		scaleRepository: scaleMock,
+		publishers:      []domain.ScalePublisher{publisherMock},
+	}
+
+	tests := []struct {
+		name    string
+		args    string
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name:    "success",
+			args:    "2022-02-01",
+			wantErr: false,
+			mock: func() {
+				scaleMock.EXPECT().Restore(gomock.Any(), date).Return(nil)
+				scaleMock.EXPECT().GetScale(gomock.Any(), date).Return([]domain.Scale{{Date: date, Min: 45, Max: 50, Difference: 5}}, nil)
+				publisherMock.EXPECT().Publish(gomock.Any()).Do(func(event domain.ScaleEvent) {
+					assert.Equal(t, domain.ScaleRestored, event.Type)
+					assert.Equal(t, domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5}, event.Scale)
+				})
+			},
+		},
+		{
+			name:    "error param",
+			args:    "date",
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name:    "not found",
+			args:    "2022-02-01",
+			wantErr: true,
+			mock: func() {
+				scaleMock.EXPECT().Restore(gomock.Any(), date).Return(domain.ErrNotFound)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+			err := uc.Restore(context.Background(), test.args)
 			assert.Equal(t, test.wantErr, err != nil)
 		})
 	}
 }
+
+func TestPurge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Date(2022, 2, 1, 7, 0, 0, 0, helper.GetLocation())
+	original := helper.Now
+	helper.Now = func() time.Time { return now }
+	defer func() { helper.Now = original }()
+
+	scaleMock := mock_domain.NewMockScaleRepository(ctrl)
+
+	uc := &scaleUsecase{
+		scaleRepository: scaleMock,
+	}
+
+	scaleMock.EXPECT().Purge(gomock.Any(), now.Add(-24*time.Hour)).Return(nil)
+
+	assert.NoError(t, uc.Purge(context.Background(), 24*time.Hour))
+	assert.Equal(t, domain.ErrBadParamInput, uc.Purge(context.Background(), 0))
+}
+
+func TestGetHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	date := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	historyMock := mock_domain.NewMockScaleHistoryRepository(ctrl)
+
+	uc := &scaleUsecase{
+		scaleHistoryRepository: historyMock,
+	}
+
+	tests := []struct {
+		name       string
+		args       string
+		wantResult []domain.ScaleHistory
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success",
+			args: "2022-02-01",
+			wantResult: []domain.ScaleHistory{
+				{Date: date, Action: domain.ScaleCreated},
+			},
+			wantErr: false,
+			mock: func() {
+				historyMock.EXPECT().GetHistory(date).Return([]domain.ScaleHistory{
+					{Date: date, Action: domain.ScaleCreated},
+				}, nil)
+			},
+		},
+		{
+			name:    "error param",
+			args:    "date",
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name:    "error",
+			args:    "2022-02-01",
+			wantErr: true,
+			mock: func() {
+				historyMock.EXPECT().GetHistory(date).Return(nil, errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+			got, err := uc.GetHistory(context.Background(), test.args)
+			assert.Equal(t, test.wantErr, err != nil)
+			assert.Equal(t, test.wantResult, got)
+		})
+	}
+}
+
+func TestPublish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	date, _ := time.Parse(common.TimeLayout, "2022-02-01")
+	now := time.Date(2022, 2, 1, 7, 0, 0, 0, helper.GetLocation())
+	original := helper.Now
+	helper.Now = func() time.Time { return now }
+	defer func() { helper.Now = original }()
+
+	scaleMock := mock_domain.NewMockScaleRepository(ctrl)
+	publisherMock := mock_domain.NewMockScalePublisher(ctrl)
+
+	uc := NewScaleUsecase(scaleMock, nil, logrus.New(), publisherMock)
+
+	tests := []struct {
+		name string
+		call func() error
+		mock func()
+	}{
+		{
+			name: "create",
+			call: func() error {
+				return uc.Create(context.Background(), &domain.Scale{Date: date, Min: 45, Max: 50})
+			},
+			mock: func() {
+				scaleMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				publisherMock.EXPECT().Publish(domain.ScaleEvent{
+					Type:       domain.ScaleCreated,
+					Scale:      domain.Scale{Date: date, Min: 45, Max: 50, Difference: 5},
+					OccurredAt: now,
+				})
+			},
+		},
+		{
+			name: "update",
+			call: func() error {
+				return uc.Update(context.Background(), &domain.Scale{Date: date, Min: 46, Max: 50})
+			},
+			mock: func() {
+				scaleMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+				publisherMock.EXPECT().Publish(domain.ScaleEvent{
+					Type:       domain.ScaleUpdated,
+					Scale:      domain.Scale{Date: date, Min: 46, Max: 50, Difference: 4},
+					OccurredAt: now,
+				})
+			},
+		},
+		{
+			name: "delete",
+			call: func() error {
+				return uc.Delete(context.Background(), "2022-02-01", 0)
+			},
+			mock: func() {
+				scaleMock.EXPECT().Delete(gomock.Any(), date, 0).Return(nil)
+				publisherMock.EXPECT().Publish(domain.ScaleEvent{
+					Type:       domain.ScaleDeleted,
+					Scale:      domain.Scale{Date: date},
+					OccurredAt: now,
+				})
+			},
+		},
+		{
+			name: "error not published",
+			call: func() error {
+				return uc.Delete(context.Background(), "2022-02-01", 0)
+			},
+			mock: func() {
+				scaleMock.EXPECT().Delete(gomock.Any(), date, 0).Return(errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+			test.call()
+		})
+	}
+}
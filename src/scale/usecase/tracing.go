@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/scale/src/common"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/scale/src/scale/usecase"
+
+type tracingScaleUsecase struct {
+	domain.ScaleUsecase
+	tracer trace.Tracer
+}
+
+// NewTracingScaleUsecase wraps any ScaleUsecase, starting a span for every call
+// with the global tracer provider.
+func NewTracingScaleUsecase(scaleUsecase domain.ScaleUsecase) domain.ScaleUsecase {
+	return &tracingScaleUsecase{
+		ScaleUsecase: scaleUsecase,
+		tracer:       otel.Tracer(instrumentationName),
+	}
+}
+
+func (t *tracingScaleUsecase) start(ctx context.Context, method string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, "ScaleUsecase."+method, trace.WithAttributes(attributes...))
+}
+
+func (t *tracingScaleUsecase) Create(ctx context.Context, param *domain.Scale) (err error) {
+	ctx, span := t.start(ctx, "Create", attribute.String("scale.date", param.Date.Format(common.TimeLayout)))
+	defer func() { helper.EndSpan(span, err) }()
+
+	return t.ScaleUsecase.Create(ctx, param)
+}
+
+func (t *tracingScaleUsecase) GetScales(ctx context.Context) (scales *domain.ScaleResponse, err error) {
+	ctx, span := t.start(ctx, "GetScales")
+	defer func() { helper.EndSpan(span, err) }()
+
+	scales, err = t.ScaleUsecase.GetScales(ctx)
+	if scales != nil {
+		span.SetAttributes(attribute.Int("scale.count", len(scales.Scales)))
+	}
+	return scales, err
+}
+
+func (t *tracingScaleUsecase) GetScalesInRange(ctx context.Context, from, to string) (scales []domain.Scale, err error) {
+	ctx, span := t.start(ctx, "GetScalesInRange", attribute.String("scale.from", from), attribute.String("scale.to", to))
+	defer func() { helper.EndSpan(span, err) }()
+
+	scales, err = t.ScaleUsecase.GetScalesInRange(ctx, from, to)
+	span.SetAttributes(attribute.Int("scale.count", len(scales)))
+	return scales, err
+}
+
+func (t *tracingScaleUsecase) GetSummary(ctx context.Context, from, to string) (summary *domain.ScaleSummary, err error) {
+	ctx, span := t.start(ctx, "GetSummary", attribute.String("scale.from", from), attribute.String("scale.to", to))
+	defer func() { helper.EndSpan(span, err) }()
+
+	summary, err = t.ScaleUsecase.GetSummary(ctx, from, to)
+	if summary != nil {
+		span.SetAttributes(attribute.Int("scale.count", summary.Count))
+	}
+	return summary, err
+}
+
+func (t *tracingScaleUsecase) GetScale(ctx context.Context, date string) (scales []domain.Scale, err error) {
+	ctx, span := t.start(ctx, "GetScale", attribute.String("scale.date", date))
+	defer func() { helper.EndSpan(span, err) }()
+
+	scales, err = t.ScaleUsecase.GetScale(ctx, date)
+	span.SetAttributes(attribute.Int("scale.count", len(scales)))
+	return scales, err
+}
+
+func (t *tracingScaleUsecase) Update(ctx context.Context, param *domain.Scale) (err error) {
+	ctx, span := t.start(ctx, "Update", attribute.String("scale.date", param.Date.Format(common.TimeLayout)))
+	defer func() { helper.EndSpan(span, err) }()
+
+	return t.ScaleUsecase.Update(ctx, param)
+}
+
+func (t *tracingScaleUsecase) Delete(ctx context.Context, date string, version int) (err error) {
+	ctx, span := t.start(ctx, "Delete", attribute.String("scale.date", date), attribute.Int("scale.version", version))
+	defer func() { helper.EndSpan(span, err) }()
+
+	return t.ScaleUsecase.Delete(ctx, date, version)
+}
+
+func (t *tracingScaleUsecase) GetHistory(ctx context.Context, date string) (history []domain.ScaleHistory, err error) {
+	ctx, span := t.start(ctx, "GetHistory", attribute.String("scale.date", date))
+	defer func() { helper.EndSpan(span, err) }()
+
+	history, err = t.ScaleUsecase.GetHistory(ctx, date)
+	span.SetAttributes(attribute.Int("scale.count", len(history)))
+	return history, err
+}
+
+func (t *tracingScaleUsecase) GetTrash(ctx context.Context) (scales []domain.Scale, err error) {
+	ctx, span := t.start(ctx, "GetTrash")
+	defer func() { helper.EndSpan(span, err) }()
+
+	scales, err = t.ScaleUsecase.GetTrash(ctx)
+	span.SetAttributes(attribute.Int("scale.count", len(scales)))
+	return scales, err
+}
+
+func (t *tracingScaleUsecase) Restore(ctx context.Context, date string) (err error) {
+	ctx, span := t.start(ctx, "Restore", attribute.String("scale.date", date))
+	defer func() { helper.EndSpan(span, err) }()
+
+	return t.ScaleUsecase.Restore(ctx, date)
+}
+
+func (t *tracingScaleUsecase) Purge(ctx context.Context, retention time.Duration) (err error) {
+	ctx, span := t.start(ctx, "Purge", attribute.String("scale.retention", retention.String()))
+	defer func() { helper.EndSpan(span, err) }()
+
+	return t.ScaleUsecase.Purge(ctx, retention)
+}
+
+func (t *tracingScaleUsecase) Batch(ctx context.Context, operations []domain.ScaleOperation) (results []domain.ScaleOperationResult, err error) {
+	ctx, span := t.start(ctx, "Batch", attribute.Int("scale.operations", len(operations)))
+	defer func() { helper.EndSpan(span, err) }()
+
+	return t.ScaleUsecase.Batch(ctx, operations)
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/scale/src/domain"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	scaleMock := mock_domain.NewMockScaleUsecase(ctrl)
+	uc := NewTracingScaleUsecase(scaleMock)
+
+	tests := []struct {
+		name           string
+		call           func() error
+		wantName       string
+		wantAttributes []attribute.KeyValue
+		wantStatus     codes.Code
+		mock           func()
+	}{
+		{
+			name: "get scales",
+			call: func() error {
+				_, err := uc.GetScales(context.Background())
+				return err
+			},
+			wantName:       "ScaleUsecase.GetScales",
+			wantAttributes: []attribute.KeyValue{attribute.Int("scale.count", 2)},
+			wantStatus:     codes.Unset,
+			mock: func() {
+				scaleMock.EXPECT().GetScales(gomock.Any()).DoAndReturn(func(ctx context.Context) (*domain.ScaleResponse, error) {
+					// the wrapped usecase runs inside the span
+					assert.True(t, trace.SpanContextFromContext(ctx).IsValid())
+					return &domain.ScaleResponse{Scales: make([]domain.Scale, 2)}, nil
+				})
+			},
+		},
+		{
+			name: "get summary",
+			call: func() error {
+				_, err := uc.GetSummary(context.Background(), "2022-02-01", "")
+				return err
+			},
+			wantName: "ScaleUsecase.GetSummary",
+			wantAttributes: []attribute.KeyValue{
+				attribute.String("scale.from", "2022-02-01"),
+				attribute.Int("scale.count", 3),
+			},
+			wantStatus: codes.Unset,
+			mock: func() {
+				scaleMock.EXPECT().GetSummary(gomock.Any(), "2022-02-01", "").Return(&domain.ScaleSummary{Count: 3}, nil)
+			},
+		},
+		{
+			name: "delete error",
+			call: func() error {
+				return uc.Delete(context.Background(), "2022-02-01", 3)
+			},
+			wantName: "ScaleUsecase.Delete",
+			wantAttributes: []attribute.KeyValue{
+				attribute.String("scale.date", "2022-02-01"),
+				attribute.Int("scale.version", 3),
+			},
+			wantStatus: codes.Error,
+			mock: func() {
+				scaleMock.EXPECT().Delete(gomock.Any(), "2022-02-01", 3).Return(errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+			test.call()
+
+			spans := recorder.Ended()
+			span := spans[len(spans)-1]
+			assert.Equal(t, test.wantName, span.Name())
+			for _, attribute := range test.wantAttributes {
+				assert.Contains(t, span.Attributes(), attribute)
+			}
+			assert.Equal(t, test.wantStatus, span.Status().Code)
+		})
+	}
+}
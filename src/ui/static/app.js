@@ -0,0 +1,149 @@
+(function () {
+	'use strict';
+
+	var form = document.getElementById('scale-form');
+	var formTitle = document.getElementById('form-title');
+	var cancel = document.getElementById('cancel');
+	var rows = document.getElementById('scales');
+	var message = document.getElementById('message');
+	var editing = false;
+
+	function request(method, path, body) {
+		var options = { method: method, headers: {} };
+		if (body !== undefined) {
+			options.headers['Content-Type'] = 'application/json';
+			options.body = JSON.stringify(body);
+		}
+
+		return fetch(path, options).then(function (res) {
+			return res.json().catch(function () {
+				return { code: res.status, message: res.statusText };
+			});
+		}).then(function (data) {
+			if (data.code !== 200) {
+				throw new Error(data.message + (data.errors ? ': ' + data.errors : ''));
+			}
+			return data;
+		});
+	}
+
+	function notify(text, success) {
+		message.textContent = text;
+		message.className = success ? 'message success' : 'message';
+		message.hidden = false;
+	}
+
+	// dates are serialized in the server location, the first 10 characters
+	// are the day the reading belongs to
+	function day(date) {
+		return date.substring(0, 10);
+	}
+
+	function cell(text) {
+		var td = document.createElement('td');
+		td.textContent = text;
+		return td;
+	}
+
+	function button(text, onClick) {
+		var b = document.createElement('button');
+		b.type = 'button';
+		b.textContent = text;
+		b.addEventListener('click', onClick);
+		return b;
+	}
+
+	function render(data) {
+		var average = data.average || {};
+		document.getElementById('average-min').textContent = average.min !== undefined ? average.min : '-';
+		document.getElementById('average-max').textContent = average.max !== undefined ? average.max : '-';
+		document.getElementById('average-difference').textContent = average.difference !== undefined ? average.difference : '-';
+		document.getElementById('chart').src = '/scales/chart.svg?moving_average=3&t=' + Date.now();
+
+		rows.textContent = '';
+		(data.scales || []).forEach(function (scale) {
+			var tr = document.createElement('tr');
+			tr.appendChild(cell(day(scale.date)));
+			tr.appendChild(cell(scale.min));
+			tr.appendChild(cell(scale.max));
+			tr.appendChild(cell(scale.difference));
+
+			var actions = document.createElement('td');
+			actions.className = 'actions';
+			actions.appendChild(button('Edit', function () { edit(scale); }));
+			actions.appendChild(button('Delete', function () { remove(scale); }));
+			tr.appendChild(actions);
+
+			rows.appendChild(tr);
+		});
+	}
+
+	function load() {
+		return request('GET', '/scales').then(function (res) {
+			render(res.data);
+		}).catch(function (err) {
+			render({});
+			notify(err.message, false);
+		});
+	}
+
+	function edit(scale) {
+		editing = true;
+		form.date.value = day(scale.date);
+		form.date.readOnly = true;
+		form.min.value = scale.min;
+		form.max.value = scale.max;
+		formTitle.textContent = 'Edit reading';
+		cancel.hidden = false;
+	}
+
+	function reset() {
+		editing = false;
+		form.reset();
+		form.date.readOnly = false;
+		formTitle.textContent = 'Add reading';
+		cancel.hidden = true;
+	}
+
+	function remove(scale) {
+		if (!window.confirm('Delete reading of ' + day(scale.date) + '?')) {
+			return;
+		}
+		request('DELETE', '/scale?date=' + encodeURIComponent(day(scale.date))).then(function (res) {
+			notify(res.message, true);
+			return load();
+		}).catch(function (err) {
+			notify(err.message, false);
+		});
+	}
+
+	form.addEventListener('submit', function (event) {
+		event.preventDefault();
+		var payload = {
+			date: form.date.value,
+			min: parseInt(form.min.value, 10),
+			max: parseInt(form.max.value, 10)
+		};
+
+		request(editing ? 'PATCH' : 'POST', '/scale', payload).then(function (res) {
+			notify(res.message, true);
+			reset();
+			return load();
+		}).catch(function (err) {
+			notify(err.message, false);
+		});
+	});
+
+	cancel.addEventListener('click', reset);
+
+	if (window.EventSource) {
+		var stream = new EventSource('/scales/stream');
+		['scale.created', 'scale.updated', 'scale.deleted', 'scale.restored', 'reset'].forEach(function (type) {
+			stream.addEventListener(type, function () {
+				load();
+			});
+		});
+	}
+
+	load();
+})();
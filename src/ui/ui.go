@@ -0,0 +1,25 @@
+package ui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/labstack/echo"
+)
+
+//go:embed static
+var static embed.FS
+
+func NewUIHandler(e *echo.Echo) {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+	fileServer := http.StripPrefix("/ui/", http.FileServer(http.FS(files)))
+
+	e.GET("/ui", func(c echo.Context) error {
+		return c.Redirect(http.StatusMovedPermanently, "/ui/")
+	})
+	e.GET("/ui/*", echo.WrapHandler(fileServer))
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUIHandler(t *testing.T) {
+	e := echo.New()
+	NewUIHandler(e)
+
+	tests := []struct {
+		name        string
+		args        string
+		wantCode    int
+		wantType    string
+		wantContain string
+	}{
+		{
+			name:     "redirect",
+			args:     "/ui",
+			wantCode: http.StatusMovedPermanently,
+		},
+		{
+			name:        "index",
+			args:        "/ui/",
+			wantCode:    http.StatusOK,
+			wantType:    "text/html; charset=utf-8",
+			wantContain: "<title>Scale Dashboard</title>",
+		},
+		{
+			name:        "script",
+			args:        "/ui/app.js",
+			wantCode:    http.StatusOK,
+			wantType:    "javascript",
+			wantContain: "/scales/chart.svg",
+		},
+		{
+			name:     "not found",
+			args:     "/ui/missing.js",
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, test.args, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.wantCode, rec.Code)
+			assert.Contains(t, rec.Header().Get(echo.HeaderContentType), test.wantType)
+			assert.Contains(t, rec.Body.String(), test.wantContain)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+)
+
+// a webhook is a URL, a secret and its event types
+const webhookBodyLimit = 16 << 10
+
+type webhookHandler struct {
+	webhookUsecase domain.WebhookUsecase
+}
+
+func NewWebhookHandler(e *echo.Echo, webhookUsecase domain.WebhookUsecase) {
+	handler := &webhookHandler{
+		webhookUsecase: webhookUsecase,
+	}
+
+	e.POST("/webhook", handler.Create, helper.BodyLimit(webhookBodyLimit))
+	e.GET("/webhooks", handler.GetWebhooks)
+	e.DELETE("/webhook", handler.Delete)
+	e.GET("/webhook/deliveries", handler.GetDeliveries)
+}
+
+func (h *webhookHandler) Create(c echo.Context) error {
+	c.Echo().Validator = helper.NewValidator()
+	payload := &domain.WebhookParam{}
+	err := c.Bind(payload)
+	if err != nil {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed create webhook", nil, err.Error()))
+	}
+	err = c.Validate(payload)
+	if err != nil {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed create webhook", nil, err.Error()))
+	}
+
+	webhook, err := h.webhookUsecase.Create(payload)
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed create webhook", nil, err.Error()))
+	}
+
+	data := helper.Response(200, "Success create webhook", webhook, nil)
+	return c.JSON(http.StatusOK, data)
+}
+
+func (h *webhookHandler) GetWebhooks(c echo.Context) error {
+	webhooks, err := h.webhookUsecase.GetWebhooks()
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed get webhooks", nil, err.Error()))
+	}
+
+	data := helper.Response(200, "Success get webhooks", webhooks, nil)
+	return c.JSON(http.StatusOK, data)
+}
+
+func (h *webhookHandler) Delete(c echo.Context) error {
+	id := c.QueryParam("id")
+	if id == "" {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed delete webhook", nil, domain.ErrBadParamInput.Error()))
+	}
+
+	err := h.webhookUsecase.Delete(id)
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed delete webhook", nil, err.Error()))
+	}
+
+	data := helper.Response(200, "Success delete webhook", nil, nil)
+	return c.JSON(http.StatusOK, data)
+}
+
+func (h *webhookHandler) GetDeliveries(c echo.Context) error {
+	id := c.QueryParam("id")
+	if id == "" {
+		code := http.StatusBadRequest
+		return c.JSON(code, helper.Response(code, "Failed get deliveries", nil, domain.ErrBadParamInput.Error()))
+	}
+
+	deliveries, err := h.webhookUsecase.GetDeliveries(id)
+	if err != nil {
+		code := helper.GetStatusCode(err)
+		return c.JSON(code, helper.Response(code, "Failed get deliveries", nil, err.Error()))
+	}
+
+	data := helper.Response(200, "Success get deliveries", deliveries, nil)
+	return c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	helper.InitTime()
+}
+
+func TestCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	createdAt := time.Date(2022, 2, 1, 7, 0, 0, 0, helper.GetLocation())
+	webhookMock := mock_domain.NewMockWebhookUsecase(ctrl)
+
+	tests := []struct {
+		name       string
+		args       string
+		wantCode   int
+		wantResult string
+		mock       func()
+	}{
+		{
+			name:     "success",
+			args:     `{"url":"http://localhost/hook","events":["scale.created"]}`,
+			wantCode: http.StatusOK,
+			wantResult: `{"code":200,"message":"Success create webhook","data":{"id":"1","url":"http://localhost/hook","secret":"secret","events":["scale.created"],"created_at":"2022-02-01T07:00:00+07:00"},"errors":null}
+`,
+			mock: func() {
+				webhookMock.EXPECT().Create(&domain.WebhookParam{
+					URL:    "http://localhost/hook",
+					Events: []string{"scale.created"},
+				}).Return(&domain.Webhook{
+					ID:        "1",
+					URL:       "http://localhost/hook",
+					Secret:    "secret",
+					Events:    []string{"scale.created"},
+					CreatedAt: createdAt,
+				}, nil)
+			},
+		},
+		{
+			name:     "error validate url",
+			args:     `{"url":"localhost"}`,
+			wantCode: http.StatusBadRequest,
+			wantResult: `{"code":400,"message":"Failed create webhook","data":null,"errors":"Key: 'WebhookParam.URL' Error:Field validation for 'URL' failed on the 'url' tag"}
+`,
+			mock: func() {},
+		},
+		{
+			name:     "error validate event",
+			args:     `{"url":"http://localhost/hook","events":["scale.read"]}`,
+			wantCode: http.StatusBadRequest,
+			wantResult: `{"code":400,"message":"Failed create webhook","data":null,"errors":"Key: 'WebhookParam.Events[0]' Error:Field validation for 'Events[0]' failed on the 'oneof' tag"}
+`,
+			mock: func() {},
+		},
+		{
+			name:     "error",
+			args:     `{"url":"http://localhost/hook"}`,
+			wantCode: http.StatusInternalServerError,
+			wantResult: `{"code":500,"message":"Failed create webhook","data":null,"errors":"some error"}
+`,
+			mock: func() {
+				webhookMock.EXPECT().Create(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(test.args))
+			req.Header.Set("content-type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			h := webhookHandler{
+				webhookUsecase: webhookMock,
+			}
+
+			test.mock()
+
+			if assert.NoError(t, h.Create(c)) {
+				assert.Equal(t, test.wantCode, rec.Code)
+				assert.Equal(t, test.wantResult, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetWebhooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	webhookMock := mock_domain.NewMockWebhookUsecase(ctrl)
+
+	tests := []struct {
+		name       string
+		wantResult string
+		mock       func()
+	}{
+		{
+			name: "success",
+			wantResult: `{"code":200,"message":"Success get webhooks","data":[{"id":"1","url":"http://localhost/hook","events":null,"created_at":"0001-01-01T00:00:00Z"}],"errors":null}
+`,
+			mock: func() {
+				webhookMock.EXPECT().GetWebhooks().Return([]domain.Webhook{
+					{ID: "1", URL: "http://localhost/hook"},
+				}, nil)
+			},
+		},
+		{
+			name: "error",
+			wantResult: `{"code":500,"message":"Failed get webhooks","data":null,"errors":"some error"}
+`,
+			mock: func() {
+				webhookMock.EXPECT().GetWebhooks().Return(nil, errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/webhooks", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			h := webhookHandler{
+				webhookUsecase: webhookMock,
+			}
+
+			test.mock()
+
+			if assert.NoError(t, h.GetWebhooks(c)) {
+				assert.Equal(t, test.wantResult, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	webhookMock := mock_domain.NewMockWebhookUsecase(ctrl)
+
+	tests := []struct {
+		name       string
+		args       string
+		wantResult string
+		mock       func()
+	}{
+		{
+			name: "success",
+			args: "1",
+			wantResult: `{"code":200,"message":"Success delete webhook","data":null,"errors":null}
+`,
+			mock: func() {
+				webhookMock.EXPECT().Delete("1").Return(nil)
+			},
+		},
+		{
+			name: "error param",
+			wantResult: `{"code":400,"message":"Failed delete webhook","data":null,"errors":"given param is not valid"}
+`,
+			mock: func() {},
+		},
+		{
+			name: "not found",
+			args: "2",
+			wantResult: `{"code":404,"message":"Failed delete webhook","data":null,"errors":"your requested item is not found"}
+`,
+			mock: func() {
+				webhookMock.EXPECT().Delete("2").Return(domain.ErrNotFound)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodDelete, "/webhook?id="+test.args, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			h := webhookHandler{
+				webhookUsecase: webhookMock,
+			}
+
+			test.mock()
+
+			if assert.NoError(t, h.Delete(c)) {
+				assert.Equal(t, test.wantResult, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	webhookMock := mock_domain.NewMockWebhookUsecase(ctrl)
+
+	tests := []struct {
+		name       string
+		args       string
+		wantResult string
+		mock       func()
+	}{
+		{
+			name: "success",
+			args: "1",
+			wantResult: `{"code":200,"message":"Success get deliveries","data":[{"id":"a","webhook_id":"1","event":"scale.created","attempt":1,"status_code":200,"error":"","success":true,"created_at":"0001-01-01T00:00:00Z"}],"errors":null}
+`,
+			mock: func() {
+				webhookMock.EXPECT().GetDeliveries("1").Return([]domain.WebhookDelivery{
+					{
+						ID:         "a",
+						WebhookID:  "1",
+						Event:      domain.ScaleCreated,
+						Attempt:    1,
+						StatusCode: 200,
+						Success:    true,
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "error param",
+			wantResult: `{"code":400,"message":"Failed get deliveries","data":null,"errors":"given param is not valid"}
+`,
+			mock: func() {},
+		},
+		{
+			name: "not found",
+			args: "2",
+			wantResult: `{"code":404,"message":"Failed get deliveries","data":null,"errors":"your requested item is not found"}
+`,
+			mock: func() {
+				webhookMock.EXPECT().GetDeliveries("2").Return(nil, domain.ErrNotFound)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/webhook/deliveries?id="+test.args, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			h := webhookHandler{
+				webhookUsecase: webhookMock,
+			}
+
+			test.mock()
+
+			if assert.NoError(t, h.GetDeliveries(c)) {
+				assert.Equal(t, test.wantResult, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"sync"
+
+	"github.com/scale/src/domain"
+)
+
+// MaxDeliveries is how many delivery attempts are kept per webhook, older
+// ones are dropped.
+const MaxDeliveries = 100
+
+type webhookRepository struct {
+	mu         sync.RWMutex
+	webhooks   []domain.Webhook         // asume this is db
+	deliveries []domain.WebhookDelivery // written concurrently by the dispatcher
+}
+
+func NewWebhookRepository() domain.WebhookRepository {
+	return &webhookRepository{}
+}
+
+func (w *webhookRepository) Create(param *domain.Webhook) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.webhooks = append(w.webhooks, *param)
+	return nil
+}
+
+func (w *webhookRepository) GetWebhooks() ([]domain.Webhook, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	webhooks := make([]domain.Webhook, len(w.webhooks))
+	copy(webhooks, w.webhooks)
+	return webhooks, nil
+}
+
+func (w *webhookRepository) GetWebhook(id string) (*domain.Webhook, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for _, webhook := range w.webhooks {
+		if webhook.ID == id {
+			return &webhook, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (w *webhookRepository) Delete(id string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for i, webhook := range w.webhooks {
+		if webhook.ID == id {
+			w.webhooks = append(w.webhooks[:i], w.webhooks[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (w *webhookRepository) CreateDelivery(param *domain.WebhookDelivery) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	kept := 0
+	for _, delivery := range w.deliveries {
+		if delivery.WebhookID == param.WebhookID {
+			kept++
+		}
+	}
+	if kept >= MaxDeliveries {
+		for i, delivery := range w.deliveries {
+			if delivery.WebhookID == param.WebhookID {
+				w.deliveries = append(w.deliveries[:i], w.deliveries[i+1:]...)
+				break
+			}
+		}
+	}
+	w.deliveries = append(w.deliveries, *param)
+	return nil
+}
+
+func (w *webhookRepository) GetDeliveries(webhookID string) ([]domain.WebhookDelivery, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	deliveries := []domain.WebhookDelivery{}
+	for _, delivery := range w.deliveries {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
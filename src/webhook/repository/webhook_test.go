@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/scale/src/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate(t *testing.T) {
+	repo := &webhookRepository{}
+
+	tests := []struct {
+		name    string
+		args    *domain.Webhook
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: &domain.Webhook{
+				ID:  "1",
+				URL: "http://localhost/hook",
+			},
+			wantErr: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := repo.Create(test.args)
+			assert.Equal(t, test.wantErr, err != nil)
+			assert.Equal(t, []domain.Webhook{*test.args}, repo.webhooks)
+		})
+	}
+}
+
+func TestGetWebhook(t *testing.T) {
+	repo := &webhookRepository{
+		webhooks: []domain.Webhook{
+			{ID: "1", URL: "http://localhost/1"},
+			{ID: "2", URL: "http://localhost/2"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		args       string
+		wantResult *domain.Webhook
+		wantErr    error
+	}{
+		{
+			name:       "success",
+			args:       "2",
+			wantResult: &domain.Webhook{ID: "2", URL: "http://localhost/2"},
+		},
+		{
+			name:    "not found",
+			args:    "3",
+			wantErr: domain.ErrNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := repo.GetWebhook(test.args)
+			assert.Equal(t, test.wantErr, err)
+			assert.Equal(t, test.wantResult, got)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	repo := &webhookRepository{
+		webhooks: []domain.Webhook{
+			{ID: "1", URL: "http://localhost/1"},
+			{ID: "2", URL: "http://localhost/2"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		args       string
+		wantResult []domain.Webhook
+		wantErr    error
+	}{
+		{
+			name:       "success",
+			args:       "1",
+			wantResult: []domain.Webhook{{ID: "2", URL: "http://localhost/2"}},
+		},
+		{
+			name:       "not found",
+			args:       "1",
+			wantResult: []domain.Webhook{{ID: "2", URL: "http://localhost/2"}},
+			wantErr:    domain.ErrNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := repo.Delete(test.args)
+			assert.Equal(t, test.wantErr, err)
+
+			got, _ := repo.GetWebhooks()
+			assert.Equal(t, test.wantResult, got)
+		})
+	}
+}
+
+func TestGetDeliveries(t *testing.T) {
+	repo := &webhookRepository{}
+	repo.CreateDelivery(&domain.WebhookDelivery{ID: "a", WebhookID: "1", Attempt: 1})
+	repo.CreateDelivery(&domain.WebhookDelivery{ID: "b", WebhookID: "2", Attempt: 1})
+	repo.CreateDelivery(&domain.WebhookDelivery{ID: "a", WebhookID: "1", Attempt: 2})
+
+	tests := []struct {
+		name       string
+		args       string
+		wantResult []domain.WebhookDelivery
+	}{
+		{
+			name: "success",
+			args: "1",
+			wantResult: []domain.WebhookDelivery{
+				{ID: "a", WebhookID: "1", Attempt: 1},
+				{ID: "a", WebhookID: "1", Attempt: 2},
+			},
+		},
+		{
+			name:       "empty",
+			args:       "3",
+			wantResult: []domain.WebhookDelivery{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := repo.GetDeliveries(test.args)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantResult, got)
+		})
+	}
+}
+
+func TestCreateDeliveryCap(t *testing.T) {
+	repo := NewWebhookRepository()
+	for i := 1; i <= MaxDeliveries+1; i++ {
+		repo.CreateDelivery(&domain.WebhookDelivery{ID: "a", WebhookID: "1", Attempt: i})
+	}
+	repo.CreateDelivery(&domain.WebhookDelivery{ID: "b", WebhookID: "2", Attempt: 1})
+
+	got, err := repo.GetDeliveries("1")
+	assert.NoError(t, err)
+	if assert.Len(t, got, MaxDeliveries) {
+		assert.Equal(t, 2, got[0].Attempt, "the oldest is dropped")
+		assert.Equal(t, MaxDeliveries+1, got[MaxDeliveries-1].Attempt)
+	}
+	got, _ = repo.GetDeliveries("2")
+	assert.Len(t, got, 1)
+}
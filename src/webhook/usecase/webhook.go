@@ -0,0 +1,380 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	HeaderEvent     = "X-Scale-Event"
+	HeaderDelivery  = "X-Scale-Delivery"
+	HeaderSignature = "X-Scale-Signature"
+	HeaderTimestamp = "X-Scale-Timestamp"
+
+	// SignatureTolerance is how far X-Scale-Timestamp may be from the clock of
+	// the receiver, older deliveries should be refused as replays. Every
+	// attempt is signed anew so retries stay within it.
+	SignatureTolerance = 5 * time.Minute
+
+	defaultMaxAttempts = 5
+	defaultBackoff     = time.Second
+	defaultTimeout     = 10 * time.Second
+)
+
+// privateNetworks are the addresses webhooks may not point to besides
+// loopback, link-local and unspecified ones.
+var privateNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("10.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("172.16.0.0/12"),
+	mustParseCIDR("192.168.0.0/16"),
+	mustParseCIDR("fc00::/7"),
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+type webhookUsecase struct {
+	webhookRepository domain.WebhookRepository
+	client            *http.Client
+	maxAttempts       int
+	backoff           time.Duration
+	wg                sync.WaitGroup
+	mu                sync.Mutex
+	closed            bool
+	// done stops the retries of deliveries once closed
+	done chan struct{}
+	// checkURL refuses the URLs webhooks may not point to when created
+	checkURL func(rawURL string) error
+	// checkIP refuses the addresses deliveries may not connect to, the name
+	// checkURL resolved may resolve elsewhere by then
+	checkIP func(ip net.IP) error
+	logger  logrus.FieldLogger
+}
+
+func NewWebhookUsecase(webhookRepository domain.WebhookRepository, logger logrus.FieldLogger) domain.WebhookUsecase {
+	w := &webhookUsecase{
+		webhookRepository: webhookRepository,
+		logger:            logger,
+		maxAttempts:       defaultMaxAttempts,
+		backoff:           defaultBackoff,
+		checkURL:          publicURL,
+		checkIP:           publicIP,
+		done:              make(chan struct{}),
+	}
+	dialer := &net.Dialer{
+		Timeout: defaultTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			return w.checkIP(net.ParseIP(host))
+		},
+	}
+	w.client = &http.Client{
+		Timeout: defaultTimeout,
+		// no proxy, it would be dialed instead of the receiver
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: defaultTimeout,
+		},
+		// a redirect could lead to an address checkURL refuses
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return w
+}
+
+// Sign returns the X-Scale-Signature value receivers should compare against,
+// an HMAC-SHA256 of the X-Scale-Timestamp value, a dot and the raw body keyed
+// with the webhook secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a delivery the way receivers should, the signature must match
+// and the timestamp be within SignatureTolerance of now.
+func Verify(secret, signature, timestamp string, body []byte, now time.Time) error {
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return errors.New("signature mismatch")
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return err
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > SignatureTolerance || age < -SignatureTolerance {
+		return errors.New("timestamp outside the tolerance")
+	}
+	return nil
+}
+
+// publicURL refuses URLs other than http and https ones and those whose host
+// is or resolves to a loopback or private address, the server would otherwise
+// post to its own network on behalf of whoever creates the webhook.
+func publicURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return domain.ErrBadParamInput
+	}
+
+	ips := []net.IP{net.ParseIP(u.Hostname())}
+	if ips[0] == nil {
+		ips, err = net.LookupIP(u.Hostname())
+		if err != nil {
+			return domain.ErrBadParamInput
+		}
+	}
+	for _, ip := range ips {
+		if publicIP(ip) != nil {
+			return domain.ErrBadParamInput
+		}
+	}
+	return nil
+}
+
+// publicIP refuses loopback, link-local, unspecified and private addresses.
+func publicIP(ip net.IP) error {
+	if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("address %s is not public", ip)
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return fmt.Errorf("address %s is not public", ip)
+		}
+	}
+	return nil
+}
+
+func newID(size int) (string, error) {
+	b := make([]byte, size)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (w *webhookUsecase) Create(param *domain.WebhookParam) (*domain.Webhook, error) {
+	err := w.checkURL(param.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newID(8)
+	if err != nil {
+		return nil, err
+	}
+	secret := param.Secret
+	if secret == "" {
+		secret, err = newID(32)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	webhook := &domain.Webhook{
+		ID:        id,
+		URL:       param.URL,
+		Secret:    secret,
+		Events:    param.Events,
+		CreatedAt: helper.Now(),
+	}
+	err = w.webhookRepository.Create(webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (w *webhookUsecase) GetWebhooks() ([]domain.Webhook, error) {
+	webhooks, err := w.webhookRepository.GetWebhooks()
+	if err != nil {
+		return nil, err
+	}
+
+	// the secret is only returned once, on create
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (w *webhookUsecase) Delete(id string) error {
+	return w.webhookRepository.Delete(id)
+}
+
+func (w *webhookUsecase) GetDeliveries(id string) ([]domain.WebhookDelivery, error) {
+	_, err := w.webhookRepository.GetWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.webhookRepository.GetDeliveries(id)
+}
+
+func subscribed(webhook domain.Webhook, eventType string) bool {
+	if len(webhook.Events) == 0 {
+		return true
+	}
+	for _, event := range webhook.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Publish delivers the event to every subscribed webhook in the background so
+// scale writes never wait on receivers.
+func (w *webhookUsecase) Publish(event domain.ScaleEvent) {
+	webhooks, err := w.webhookRepository.GetWebhooks()
+	if err != nil {
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	// Close waits on wg, it must not grow afterwards
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	for _, webhook := range webhooks {
+		if !subscribed(webhook, event.Type) {
+			continue
+		}
+
+		w.wg.Add(1)
+		go func(webhook domain.Webhook) {
+			defer w.wg.Done()
+			w.deliver(webhook, event.Type, body)
+		}(webhook)
+	}
+}
+
+func (w *webhookUsecase) deliver(webhook domain.Webhook, eventType string, body []byte) {
+	id, err := newID(8)
+	if err != nil {
+		return
+	}
+
+	backoff := w.backoff
+	for attempt := 1; attempt <= w.maxAttempts; attempt++ {
+		delivery := &domain.WebhookDelivery{
+			ID:        id,
+			WebhookID: webhook.ID,
+			Event:     eventType,
+			Attempt:   attempt,
+			CreatedAt: helper.Now(),
+		}
+
+		delivery.StatusCode, err = w.send(webhook, id, eventType, body)
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		delivery.Success = err == nil
+		w.webhookRepository.CreateDelivery(delivery)
+
+		if delivery.Success {
+			return
+		}
+		logger := w.logger.WithFields(logrus.Fields{
+			"webhook_id":  webhook.ID,
+			"delivery_id": id,
+			"event":       eventType,
+		}).WithError(err)
+		if attempt == w.maxAttempts {
+			logger.Warn("webhook delivery gave up")
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-w.done:
+			logger.Warn("webhook delivery gave up on shutdown")
+			return
+		}
+		backoff *= 2
+	}
+}
+
+// Close stops publishing and retrying, then waits for the attempts in flight
+// until ctx is done.
+func (w *webhookUsecase) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.done)
+	}
+	w.mu.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *webhookUsecase) send(webhook domain.Webhook, id, eventType string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, eventType)
+	req.Header.Set(HeaderDelivery, id)
+	timestamp := strconv.FormatInt(helper.Now().Unix(), 10)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
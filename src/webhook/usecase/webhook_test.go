@@ -0,0 +1,373 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/scale/src/domain"
+	"github.com/scale/src/helper"
+	mock_domain "github.com/scale/src/mock"
+	"github.com/scale/src/webhook/repository"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	helper.InitTime()
+}
+
+func TestSign(t *testing.T) {
+	got := Sign("secret", "1643673600", []byte(`{"type":"scale.created"}`))
+	assert.Equal(t, "sha256=a23c195f783866984a1903f85eeb9077d14327105e98e3a22e9ff8232dbc49b0", got)
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"type":"scale.created"}`)
+	now := time.Unix(1643673600, 0)
+	signature := Sign("secret", "1643673600", body)
+
+	tests := []struct {
+		name      string
+		signature string
+		timestamp string
+		now       time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "success",
+			signature: signature,
+			timestamp: "1643673600",
+			now:       now.Add(SignatureTolerance),
+		},
+		{
+			name:      "replayed",
+			signature: signature,
+			timestamp: "1643673600",
+			now:       now.Add(SignatureTolerance + time.Second),
+			wantErr:   true,
+		},
+		{
+			name:      "other timestamp",
+			signature: signature,
+			timestamp: "1643673601",
+			now:       now,
+			wantErr:   true,
+		},
+		{
+			name:      "wrong signature",
+			signature: Sign("other", "1643673600", body),
+			timestamp: "1643673600",
+			now:       now,
+			wantErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Verify("secret", test.signature, test.timestamp, body, test.now)
+			assert.Equal(t, test.wantErr, err != nil)
+		})
+	}
+}
+
+func TestPublicURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		wantErr bool
+	}{
+		{name: "public", args: "https://203.0.113.10/hook"},
+		{name: "loopback", args: "http://127.0.0.1:8080/hook", wantErr: true},
+		{name: "loopback name", args: "http://localhost/hook", wantErr: true},
+		{name: "private", args: "http://10.1.2.3/hook", wantErr: true},
+		{name: "link local", args: "http://169.254.169.254/latest/meta-data", wantErr: true},
+		{name: "private v6", args: "http://[fd00::1]/hook", wantErr: true},
+		{name: "unspecified", args: "http://0.0.0.0/hook", wantErr: true},
+		{name: "other scheme", args: "ftp://203.0.113.10/hook", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := publicURL(test.args)
+			assert.Equal(t, test.wantErr, err != nil)
+		})
+	}
+}
+
+func TestPublishPrivateAddress(t *testing.T) {
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer receiver.Close()
+
+	repo := repository.NewWebhookRepository()
+	uc := NewWebhookUsecase(repo, logrus.New()).(*webhookUsecase)
+	uc.backoff = time.Millisecond
+	uc.maxAttempts = 1
+	// passed when created, the name resolves to loopback by the delivery
+	uc.checkURL = func(string) error { return nil }
+
+	webhook, _ := uc.Create(&domain.WebhookParam{URL: receiver.URL})
+	uc.Publish(domain.ScaleEvent{Type: domain.ScaleCreated})
+	uc.wg.Wait()
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+	deliveries, _ := uc.GetDeliveries(webhook.ID)
+	if assert.Len(t, deliveries, 1) {
+		assert.False(t, deliveries[0].Success)
+		assert.Contains(t, deliveries[0].Error, "address 127.0.0.1 is not public")
+	}
+}
+
+func TestCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	webhookMock := mock_domain.NewMockWebhookRepository(ctrl)
+	uc := NewWebhookUsecase(webhookMock, logrus.New())
+
+	tests := []struct {
+		name       string
+		args       *domain.WebhookParam
+		wantSecret bool
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success with secret",
+			args: &domain.WebhookParam{
+				URL:    "http://203.0.113.10/hook",
+				Secret: "secret",
+			},
+			wantErr: false,
+			mock: func() {
+				webhookMock.EXPECT().Create(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "success generate secret",
+			args: &domain.WebhookParam{
+				URL: "http://203.0.113.10/hook",
+			},
+			wantErr: false,
+			mock: func() {
+				webhookMock.EXPECT().Create(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "private url",
+			args: &domain.WebhookParam{
+				URL: "http://192.168.1.1/hook",
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error",
+			args: &domain.WebhookParam{
+				URL: "http://203.0.113.10/hook",
+			},
+			wantErr: true,
+			mock: func() {
+				webhookMock.EXPECT().Create(gomock.Any()).Return(errors.New("some error"))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+
+			got, err := uc.Create(test.args)
+			assert.Equal(t, test.wantErr, err != nil)
+			if err != nil {
+				return
+			}
+			assert.NotEmpty(t, got.ID)
+			assert.Equal(t, test.args.URL, got.URL)
+			if test.args.Secret != "" {
+				assert.Equal(t, test.args.Secret, got.Secret)
+			} else {
+				assert.Len(t, got.Secret, 64)
+			}
+		})
+	}
+}
+
+func TestGetWebhooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	webhookMock := mock_domain.NewMockWebhookRepository(ctrl)
+	uc := NewWebhookUsecase(webhookMock, logrus.New())
+
+	webhookMock.EXPECT().GetWebhooks().Return([]domain.Webhook{
+		{ID: "1", URL: "http://203.0.113.10/hook", Secret: "secret"},
+	}, nil)
+
+	got, err := uc.GetWebhooks()
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Webhook{{ID: "1", URL: "http://203.0.113.10/hook"}}, got)
+}
+
+func TestGetDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	webhookMock := mock_domain.NewMockWebhookRepository(ctrl)
+	uc := NewWebhookUsecase(webhookMock, logrus.New())
+
+	tests := []struct {
+		name    string
+		args    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			args: "1",
+			mock: func() {
+				webhookMock.EXPECT().GetWebhook("1").Return(&domain.Webhook{ID: "1"}, nil)
+				webhookMock.EXPECT().GetDeliveries("1").Return([]domain.WebhookDelivery{}, nil)
+			},
+		},
+		{
+			name:    "not found",
+			args:    "2",
+			wantErr: domain.ErrNotFound,
+			mock: func() {
+				webhookMock.EXPECT().GetWebhook("2").Return(nil, domain.ErrNotFound)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mock()
+
+			_, err := uc.GetDeliveries(test.args)
+			assert.Equal(t, test.wantErr, err)
+		})
+	}
+}
+
+func TestPublish(t *testing.T) {
+	var calls int32
+	var gotSignature, gotTimestamp, gotEvent string
+	var gotBody []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// fail the first attempt so the retry path is exercised
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		gotSignature = r.Header.Get(HeaderSignature)
+		gotTimestamp = r.Header.Get(HeaderTimestamp)
+		gotEvent = r.Header.Get(HeaderEvent)
+		gotBody, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer receiver.Close()
+
+	repo := repository.NewWebhookRepository()
+	uc := NewWebhookUsecase(repo, logrus.New()).(*webhookUsecase)
+	uc.backoff = time.Millisecond
+	// the receivers listen on loopback
+	uc.checkURL = func(string) error { return nil }
+	uc.checkIP = func(net.IP) error { return nil }
+
+	subscribed, _ := uc.Create(&domain.WebhookParam{
+		URL:    receiver.URL,
+		Secret: "secret",
+		Events: []string{domain.ScaleCreated},
+	})
+	ignored, _ := uc.Create(&domain.WebhookParam{
+		URL:    receiver.URL,
+		Events: []string{domain.ScaleDeleted},
+	})
+
+	uc.Publish(domain.ScaleEvent{Type: domain.ScaleCreated})
+	uc.wg.Wait()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, domain.ScaleCreated, gotEvent)
+	assert.NoError(t, Verify("secret", gotSignature, gotTimestamp, gotBody, helper.Now()))
+
+	deliveries, err := uc.GetDeliveries(subscribed.ID)
+	if assert.NoError(t, err) && assert.Len(t, deliveries, 2) {
+		assert.Equal(t, 1, deliveries[0].Attempt)
+		assert.Equal(t, http.StatusInternalServerError, deliveries[0].StatusCode)
+		assert.False(t, deliveries[0].Success)
+		assert.Equal(t, 2, deliveries[1].Attempt)
+		assert.Equal(t, http.StatusOK, deliveries[1].StatusCode)
+		assert.True(t, deliveries[1].Success)
+		assert.Equal(t, deliveries[0].ID, deliveries[1].ID)
+	}
+
+	deliveries, err = uc.GetDeliveries(ignored.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, deliveries)
+}
+
+func TestPublishGiveUp(t *testing.T) {
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer receiver.Close()
+
+	repo := repository.NewWebhookRepository()
+	uc := NewWebhookUsecase(repo, logrus.New()).(*webhookUsecase)
+	uc.backoff = time.Millisecond
+	// the receivers listen on loopback
+	uc.checkURL = func(string) error { return nil }
+	uc.checkIP = func(net.IP) error { return nil }
+	uc.maxAttempts = 3
+
+	webhook, _ := uc.Create(&domain.WebhookParam{URL: receiver.URL})
+
+	uc.Publish(domain.ScaleEvent{Type: domain.ScaleUpdated})
+	uc.wg.Wait()
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	deliveries, _ := uc.GetDeliveries(webhook.ID)
+	if assert.Len(t, deliveries, 3) {
+		assert.Equal(t, "unexpected status 502", deliveries[2].Error)
+	}
+}
+
+func TestClose(t *testing.T) {
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer receiver.Close()
+
+	repo := repository.NewWebhookRepository()
+	uc := NewWebhookUsecase(repo, logrus.New()).(*webhookUsecase)
+	// without Close the retries would take an hour
+	uc.backoff = time.Hour
+	// the receivers listen on loopback
+	uc.checkURL = func(string) error { return nil }
+	uc.checkIP = func(net.IP) error { return nil }
+
+	webhook, _ := uc.Create(&domain.WebhookParam{URL: receiver.URL})
+
+	uc.Publish(domain.ScaleEvent{Type: domain.ScaleUpdated})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, uc.Close(ctx))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// nothing is published once closed
+	uc.Publish(domain.ScaleEvent{Type: domain.ScaleUpdated})
+	assert.NoError(t, uc.Close(ctx))
+	deliveries, _ := uc.GetDeliveries(webhook.ID)
+	assert.Len(t, deliveries, 1)
+}